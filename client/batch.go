@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// Batch executes a list of queries against GraphQL remote
+// in a single http request. Remote endpoint must support
+// batching, accepting JSON array of queries and responding
+// with array of responses.
+func (c *Client) Batch(rs []Raw) ([]Response, error) {
+	return c.BatchContext(context.Background(), rs)
+}
+
+// BatchContext executes a list of queries in a single http request,
+// returning response for each query in the same order. Errors of
+// each query are returned in its response, error is only returned if
+// the whole batch failed. Request is cancelled when ctx is done.
+//
+// All queries are sent with POST method and headers of all queries
+// are merged, values of the same header are appended, unless they
+// were already set by another query. Persisted queries are not
+// used in batches.
+func (c *Client) BatchContext(ctx context.Context, rs []Raw) ([]Response, error) {
+	if len(rs) == 0 {
+		return nil, nil
+	}
+	retryable := true
+	header := make(http.Header)
+	var uploads []uploadPath
+	ops := make([]Raw, len(rs))
+	for i, r := range rs {
+		if r.Query == "" {
+			return nil, fmt.Errorf("query %d cannot be empty", i)
+		}
+		ops[i] = r
+		ops[i].Query = minifyQuery(r.Query)
+		retryable = retryable && c.retryable(r)
+		for k, vs := range r.Header {
+			for _, v := range vs {
+				if !hasValue(header[k], v) {
+					header[k] = append(header[k], v)
+				}
+			}
+		}
+		uploads = append(uploads, r.uploads(strconv.Itoa(i)+".")...)
+	}
+	resp, err := c.do(ctx, func() (*http.Request, error) {
+		url, err := c.endpointURL()
+		if err != nil {
+			return nil, err
+		}
+		req, err := bodyRequest(http.MethodPost, url.String(), ops, uploads)
+		if err != nil {
+			return nil, err
+		}
+		return withHeader(ctx, req, header), nil
+	}, retryable)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			fmt.Fprintln(os.Stderr, cerr) // nolint: errcheck
+		}
+	}()
+	return decodeBatchResponse(resp, len(rs))
+}
+
+// decodeBatchResponse decodes array of GraphQL responses. If remote
+// rejected the whole batch with a single GraphQL response, its errors
+// are returned.
+func decodeBatchResponse(resp *http.Response, n int) ([]Response, error) {
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	ok := resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !ok && mediaType != MediaTypeGraphQLResponse {
+		return nil, newHTTPError(resp, body)
+	}
+	var responses []Response
+	if err := json.Unmarshal(body, &responses); err != nil {
+		var single Response
+		if err := json.Unmarshal(body, &single); err == nil && len(single.Errors) != 0 {
+			return nil, single.Errors
+		}
+		return nil, newHTTPError(resp, body)
+	}
+	if len(responses) != n {
+		return nil, errors.New("number of responses does not match number of queries in batch")
+	}
+	return responses, nil
+}
+
+func hasValue(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aexol/test_util"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCaseClientBatch struct {
+	status    int
+	body      string
+	responses []Response
+	err       func(assert *assert.Assertions) test_util.ErrorAssertion
+}
+
+func (tt testCaseClientBatch) test(t *testing.T) {
+	assert := assert.New(t)
+	if tt.err == nil {
+		tt.err = test_util.NoError
+	}
+	var requests []Raw
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(b, &requests) // nolint: errcheck
+		header = r.Header
+		if tt.status != 0 {
+			w.WriteHeader(tt.status)
+		}
+		fmt.Fprint(w, tt.body)
+	}))
+	defer srv.Close()
+	responses, err := New(Config{Endpoint: srv.URL}).Batch([]Raw{
+		{
+			Query:  "query { a }",
+			Header: http.Header{"X-A": []string{"a"}},
+		},
+		{
+			Query:     "query($b: Int) {\n  b(b: $b)\n}",
+			Variables: map[string]interface{}{"b": 1},
+			Header:    http.Header{"X-B": []string{"b"}},
+		},
+	})
+	tt.err(assert)(err)
+	assert.Equal(tt.responses, responses)
+	assert.Equal([]Raw{
+		{Query: "query { a }"},
+		{Query: "query($b: Int) { b(b: $b) }", Variables: map[string]interface{}{"b": float64(1)}},
+	}, requests)
+	assert.Equal("a", header.Get("X-A"))
+	assert.Equal("b", header.Get("X-B"))
+	assert.Equal("application/json", header.Get("Content-Type"))
+}
+
+func TestClientBatch(t *testing.T) {
+	data := []testCaseClientBatch{
+		{
+			body: `[{"data":{"a":"a"}},{"data":{"b":null},"errors":[{"message":"b failed"}]}]`,
+			responses: []Response{
+				{Data: map[string]interface{}{"a": "a"}},
+				{Data: map[string]interface{}{"b": nil}, Errors: Errors{{Message: "b failed"}}},
+			},
+		},
+		{
+			body: `[{"data":{"a":"a"}}]`,
+			err:  test_util.Error,
+		},
+		{
+			status: http.StatusBadRequest,
+			body:   `{"errors":[{"message":"batching not supported"}]}`,
+			err:    test_util.Error,
+		},
+		{
+			body: `{"errors":[{"message":"batching not supported"}]}`,
+			err:  test_util.Error,
+		},
+		{
+			body: `not json`,
+			err:  test_util.Error,
+		},
+	}
+	for _, tt := range data {
+		tt.test(t)
+	}
+}
+
+func TestClientBatchHeader(t *testing.T) {
+	assert := assert.New(t)
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		fmt.Fprint(w, `[{"data":{}},{"data":{}}]`)
+	}))
+	defer srv.Close()
+	_, err := New(Config{Endpoint: srv.URL}).Batch([]Raw{
+		{
+			Query:  "query { a }",
+			Header: http.Header{"X-A": []string{"a"}, "Authorization": []string{"token"}},
+		},
+		{
+			Query:  "query { b }",
+			Header: http.Header{"X-A": []string{"b"}, "Authorization": []string{"token"}},
+		},
+	})
+	assert.NoError(err)
+	assert.Equal([]string{"a", "b"}, header["X-A"])
+	assert.Equal([]string{"token"}, header["Authorization"])
+}
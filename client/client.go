@@ -4,18 +4,26 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"mime"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
-	"regexp"
 	"strings"
+	"time"
 )
 
-var (
-	multipleSpaces = regexp.MustCompile(`[\s\p{Zs}]{2,}`)
+const (
+	// MediaTypeGraphQLResponse is a media type of response defined
+	// by GraphQL over HTTP specification
+	MediaTypeGraphQLResponse = "application/graphql-response+json"
+	// MediaTypeJSON is a legacy media type of GraphQL response
+	MediaTypeJSON = "application/json"
 )
 
 // Client is a http Client used to
@@ -24,6 +32,19 @@ type Client struct {
 	http.Client
 	// GraphQL http endpoint
 	Endpoint string
+	// InitPayload is sent with connection_init message
+	// when subscribing over websocket
+	InitPayload map[string]interface{}
+	// ConnectTimeout limits time spent on establishing
+	// connection, no limit if zero
+	ConnectTimeout time.Duration
+	// Retry is an optional policy of retrying failed requests
+	Retry *RetryPolicy
+	// Persisted enables automatic persisted queries
+	Persisted bool
+	// PersistedCache keeps hashes of queries registered
+	// on remote endpoint
+	PersistedCache PersistedQueryCache
 }
 
 // Raw GraphQL query,
@@ -37,17 +58,33 @@ type Raw struct {
 	OperationName string `json:"operationName,omitempty"`
 	// Optional http headers
 	Header http.Header `json:"-"`
-	// optional request method, defaults to POST
+	// optional request method, defaults to POST.
+	// GET requests send query in url query string
 	Method string `json:"-"`
+	// Extensions is an optional map of protocol extensions
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
 }
 
 func (r Raw) values() (url.Values, error) {
-	if r.Query == "" {
+	if r.Query == "" && r.Extensions == nil {
 		return nil, errors.New("query cannot be empty")
 	}
-	r.Query = minifyQuery(r.Query)
 	values := make(url.Values)
-	values.Add("query", r.Query)
+	if r.Query != "" {
+		query := r.Query
+		// persisted query must be sent as hashed
+		if _, ok := r.Extensions["persistedQuery"]; !ok {
+			query = minifyQuery(query)
+		}
+		values.Add("query", query)
+	}
+	if len(r.Extensions) != 0 {
+		b, err := json.Marshal(r.Extensions)
+		if err != nil {
+			return nil, err
+		}
+		values.Add("extensions", string(b))
+	}
 	if len(r.Variables) != 0 {
 		b, err := json.Marshal(r.Variables)
 		if err != nil {
@@ -61,18 +98,72 @@ func (r Raw) values() (url.Values, error) {
 	return values, nil
 }
 
+// minifyQuery replaces runs of whitespace and comments in
+// query with a single space, strings are kept as they are
 func minifyQuery(q string) string {
-	q = strings.Replace(q, "\n", "", -1)
-	q = strings.TrimSpace(q)
-	q = multipleSpaces.ReplaceAllString(q, " ")
-	return q
+	var sb strings.Builder
+	space := false
+	for i := 0; i < len(q); {
+		var end int
+		switch c := q[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = true
+			i++
+			continue
+		case c == '#':
+			for i < len(q) && q[i] != '\n' && q[i] != '\r' {
+				i++
+			}
+			space = true
+			continue
+		case strings.HasPrefix(q[i:], `"""`):
+			end = blockStringEnd(q, i+3)
+		case c == '"':
+			end = stringEnd(q, i+1)
+		default:
+			end = i + 1
+		}
+		if space && sb.Len() != 0 {
+			sb.WriteByte(' ')
+		}
+		space = false
+		sb.WriteString(q[i:end])
+		i = end
+	}
+	return sb.String()
 }
 
-func (c *Client) buildRequest(r Raw) (*http.Request, error) {
-	b, err := json.Marshal(r)
-	if err != nil {
-		return nil, err
+// stringEnd returns offset in q right after the end of
+// string starting at i, strings cannot span lines
+func stringEnd(q string, i int) int {
+	for ; i < len(q); i++ {
+		switch q[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		case '\n', '\r':
+			return i
+		}
+	}
+	return len(q)
+}
+
+// blockStringEnd returns offset in q right after
+// the end of block string starting at i
+func blockStringEnd(q string, i int) int {
+	for ; i < len(q); i++ {
+		switch {
+		case strings.HasPrefix(q[i:], `\"""`):
+			i += 3
+		case strings.HasPrefix(q[i:], `"""`):
+			return i + 3
+		}
 	}
+	return len(q)
+}
+
+func (c *Client) endpointURL() (*url.URL, error) {
 	url, err := url.Parse(c.Endpoint)
 	if err != nil || c.Endpoint == "" {
 		if c.Endpoint == "" {
@@ -80,29 +171,112 @@ func (c *Client) buildRequest(r Raw) (*http.Request, error) {
 		}
 		return nil, err
 	}
-	meth := r.Method
+	return url, nil
+}
+
+func (c *Client) buildRequest(r Raw) (*http.Request, error) {
+	url, err := c.endpointURL()
+	if err != nil {
+		return nil, err
+	}
+	meth := strings.ToUpper(r.Method)
 	if meth == "" {
-		meth = "POST"
+		meth = http.MethodPost
+	}
+	uploads := r.uploads("")
+	if meth == http.MethodGet {
+		if len(uploads) != 0 {
+			return nil, errors.New("files cannot be uploaded with GET request")
+		}
+		// GET request carries query in url
+		// and has no body
+		values, err := r.values()
+		if err != nil {
+			return nil, err
+		}
+		query := url.Query()
+		for k, v := range values {
+			query[k] = v
+		}
+		url.RawQuery = query.Encode()
+		return http.NewRequest(meth, url.String(), nil)
+	}
+	return bodyRequest(meth, url.String(), r, uploads)
+}
+
+// bodyRequest builds request sending operations in a body,
+// as a multipart request if there are any files to upload
+func bodyRequest(meth, url string, ops interface{}, uploads []uploadPath) (*http.Request, error) {
+	if len(uploads) != 0 {
+		body, contentType, err := multipartBody(ops, uploads)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequest(meth, url, body)
+		if err != nil {
+			body.Close() // nolint: errcheck
+			return nil, err
+		}
+		req.Header.Set("Content-Type", contentType)
+		return req, nil
+	}
+	b, err := json.Marshal(ops)
+	if err != nil {
+		return nil, err
 	}
-	return http.NewRequest(
+	req, err := http.NewRequest(
 		meth,
-		url.String(),
+		url,
 		bytes.NewBuffer(b),
 	)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
 }
 
-// Raw executes GraphQL query against GraphQL remote
-func (c *Client) Raw(r Raw, out interface{}) (interface{}, error) {
+// newRequest builds http request for query with
+// headers set by user and Content-Type of the body
+func (c *Client) newRequest(ctx context.Context, r Raw) (*http.Request, error) {
 	req, err := c.buildRequest(r)
 	if err != nil {
 		return nil, err
 	}
-	if r.Header == nil {
-		r.Header = make(http.Header)
+	return withHeader(ctx, req, r.Header), nil
+}
+
+// withHeader sets user headers and default Accept header on request
+func withHeader(ctx context.Context, req *http.Request, header http.Header) *http.Request {
+	req = req.WithContext(ctx)
+	contentType := req.Header.Get("Content-Type")
+	for k, v := range header {
+		req.Header[k] = append([]string(nil), v...)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
 	}
-	r.Header.Add("Content-Type", "application/json")
-	req.Header = r.Header
-	resp, err := c.Do(req)
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", MediaTypeGraphQLResponse+", "+MediaTypeJSON+";q=0.9, "+incrementalAccept)
+	}
+	return req
+}
+
+// Raw executes GraphQL query against GraphQL remote
+func (c *Client) Raw(r Raw, out interface{}) (interface{}, error) {
+	return c.RawContext(context.Background(), r, out)
+}
+
+// RawContext executes GraphQL query against GraphQL remote.
+// Request is cancelled when ctx is done.
+func (c *Client) RawContext(ctx context.Context, r Raw, out interface{}) (interface{}, error) {
+	return c.RawIncremental(ctx, r, out, nil)
+}
+
+func (c *Client) send(ctx context.Context, r Raw, out interface{}, retryable bool, onPatch func(Patch) error) (interface{}, error) {
+	resp, err := c.do(ctx, func() (*http.Request, error) {
+		return c.newRequest(ctx, r)
+	}, retryable)
 	if err != nil {
 		return nil, err
 	}
@@ -111,16 +285,67 @@ func (c *Client) Raw(r Raw, out interface{}) (interface{}, error) {
 			fmt.Fprintln(os.Stderr, cerr) // nolint: errcheck
 		}
 	}()
-	var gqlResponse Response
+	return decodeResponse(resp, out, onPatch)
+}
+
+func newHTTPError(resp *http.Response, body []byte) *HTTPError {
+	if len(body) > MaxErrorBodySize {
+		body = body[:MaxErrorBodySize]
+	}
+	return &HTTPError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header,
+		Body:       body,
+	}
+}
+
+// decodeResponse decodes GraphQL response following GraphQL over HTTP
+// rules. Response with non 2xx status is only accepted if it uses
+// application/graphql-response+json media type, as with
+// application/json it might have been returned by an intermediary
+// rather than GraphQL server. Incremental multipart/mixed response
+// is merged into a single result, calling onPatch, if not nil, with
+// each payload.
+func decodeResponse(resp *http.Response, out interface{}, onPatch func(Patch) error) (interface{}, error) {
+	mediaType, params, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	ok := resp.StatusCode >= 200 && resp.StatusCode < 300
+	if ok && mediaType == MediaTypeMultipartMixed {
+		return decodeIncremental(resp, params["boundary"], out, onPatch)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if !ok && mediaType != MediaTypeGraphQLResponse {
+		return nil, newHTTPError(resp, body)
+	}
+	var gqlResponse struct {
+		Data   interface{}     `json:"data"`
+		Errors json.RawMessage `json:"errors"`
+	}
 	if out != nil {
 		gqlResponse.Data = out
 	}
-	dec := json.NewDecoder(resp.Body)
-	if err = dec.Decode(&gqlResponse); err != nil {
-		return nil, err
+	if err := json.Unmarshal(body, &gqlResponse); err != nil {
+		return nil, newHTTPError(resp, body)
 	}
-	if len(gqlResponse.Errors) != 0 {
-		err = gqlResponse.Errors
+	var errs Errors
+	if len(gqlResponse.Errors) != 0 && string(gqlResponse.Errors) != "null" {
+		if err := json.Unmarshal(gqlResponse.Errors, &errs); err != nil {
+			return nil, newHTTPError(resp, body)
+		}
+	}
+	if !ok && len(errs) == 0 {
+		return nil, newHTTPError(resp, body)
+	}
+	if len(errs) != 0 {
+		err = errs
+	}
+	if onPatch != nil {
+		if perr := onPatch(Patch{Data: gqlResponse.Data, Errors: errs}); perr != nil {
+			return nil, perr
+		}
 	}
 	return gqlResponse.Data, err
 }
@@ -132,17 +357,58 @@ type Config struct {
 	// RoundTripper is an optional http.RoundTripper for client
 	// if not set falls back to http.DefaultTransport
 	RoundTripper http.RoundTripper
+	// InitPayload is an optional connection_init payload
+	// for websocket subscriptions
+	InitPayload map[string]interface{}
+	// ConnectTimeout is an optional limit on time spent dialing
+	// remote endpoint. Ignored if RoundTripper is set, except
+	// for websocket connections.
+	ConnectTimeout time.Duration
+	// Retry is an optional policy of retrying requests
+	// failed with transient errors, requests are not
+	// retried if nil
+	Retry *RetryPolicy
+	// Persisted enables Apollo automatic persisted queries,
+	// queries known to be registered on remote endpoint are
+	// sent as sha256 hash only
+	Persisted bool
+	// PersistedCache is an optional cache of hashes registered
+	// on remote endpoint, defaults to in memory cache
+	PersistedCache PersistedQueryCache
+}
+
+func (c *Client) dialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout:   c.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
 }
 
 // New creates new GraphQL client
 func New(cfg Config) *Client {
 	cli := &Client{
-		Endpoint: cfg.Endpoint,
+		Endpoint:       cfg.Endpoint,
+		InitPayload:    cfg.InitPayload,
+		ConnectTimeout: cfg.ConnectTimeout,
+		Retry:          cfg.Retry,
+		Persisted:      cfg.Persisted,
+		PersistedCache: cfg.PersistedCache,
+	}
+	if cli.Persisted && cli.PersistedCache == nil {
+		cli.PersistedCache = NewMemoryPersistedQueryCache()
 	}
-	if cfg.RoundTripper != nil {
+	switch {
+	case cfg.RoundTripper != nil:
 		cli.Client = http.Client{
 			Transport: cfg.RoundTripper,
 		}
+	case cfg.ConnectTimeout != 0:
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.DialContext = cli.dialer().DialContext
+		transport.TLSHandshakeTimeout = cfg.ConnectTimeout
+		cli.Client = http.Client{
+			Transport: transport,
+		}
 	}
 	return cli
 }
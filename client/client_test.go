@@ -2,12 +2,16 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/aexol/test_util"
 	"github.com/stretchr/testify/mock"
@@ -32,6 +36,14 @@ func TestMinifyQuery(t *testing.T) {
 			in:  "    qqq     qqq   \n qqq    ",
 			out: "qqq qqq qqq",
 		},
+		{
+			in:  "query {\na # comment\nb\n}",
+			out: "query { a b }",
+		},
+		{
+			in:  "{\r\n\ta(s: \"x  # y\\\"  z\")\n\tb(s: \"\"\"\n  q \\\"\"\" #\n\"\"\")\n}",
+			out: "{ a(s: \"x  # y\\\"  z\") b(s: \"\"\"\n  q \\\"\"\" #\n\"\"\") }",
+		},
 	}
 	for _, tt := range data {
 		tt.test(t)
@@ -226,3 +238,160 @@ func TestClientRaw(t *testing.T) {
 		tt.test(t)
 	}
 }
+
+func TestClientRawContext(t *testing.T) {
+	assert := assert.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := New(Config{
+		Endpoint:       srv.URL,
+		ConnectTimeout: time.Second,
+	}).RawContext(ctx, Raw{Query: "some-query"}, nil)
+	assert.Error(err)
+	assert.Equal(context.DeadlineExceeded, ctx.Err())
+}
+
+type testCaseDecodeResponse struct {
+	status      int
+	contentType string
+	body        string
+	out         interface{}
+	err         error
+}
+
+func (tt testCaseDecodeResponse) test(t *testing.T) {
+	assert := assert.New(t)
+	header := make(http.Header)
+	header.Set("Content-Type", tt.contentType)
+	resp := &http.Response{
+		StatusCode: tt.status,
+		Status:     fmt.Sprintf("%d %s", tt.status, http.StatusText(tt.status)),
+		Header:     header,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(tt.body)),
+	}
+	out, err := decodeResponse(resp, nil, nil)
+	assert.Equal(tt.out, out)
+	assert.Equal(tt.err, err)
+}
+
+func TestDecodeResponse(t *testing.T) {
+	oldMax := MaxErrorBodySize
+	MaxErrorBodySize = 8
+	defer func() {
+		MaxErrorBodySize = oldMax
+	}()
+	httpError := func(status int, contentType, body string) error {
+		header := make(http.Header)
+		header.Set("Content-Type", contentType)
+		return &HTTPError{
+			StatusCode: status,
+			Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+			Header:     header,
+			Body:       []byte(body),
+		}
+	}
+	data := []testCaseDecodeResponse{
+		{
+			status:      http.StatusOK,
+			contentType: MediaTypeJSON,
+			body:        `{"data":{"a":1}}`,
+			out:         map[string]interface{}{"a": float64(1)},
+		},
+		{
+			status:      http.StatusOK,
+			contentType: MediaTypeGraphQLResponse + "; charset=utf-8",
+			body:        `{"data":null,"errors":[{"message":"bad"}]}`,
+			err:         Errors{Error{Message: "bad"}},
+		},
+		{
+			status:      http.StatusBadRequest,
+			contentType: MediaTypeGraphQLResponse,
+			body:        `{"errors":[{"message":"bad"}]}`,
+			err:         Errors{Error{Message: "bad"}},
+		},
+		{
+			status:      http.StatusBadRequest,
+			contentType: MediaTypeGraphQLResponse,
+			body:        `{}`,
+			err:         httpError(http.StatusBadRequest, MediaTypeGraphQLResponse, `{}`),
+		},
+		{
+			status:      http.StatusBadRequest,
+			contentType: MediaTypeJSON,
+			body:        `{"errors":[{"message":"bad"}]}`,
+			err:         httpError(http.StatusBadRequest, MediaTypeJSON, `{"errors`),
+		},
+		{
+			status:      http.StatusUnauthorized,
+			contentType: "text/html",
+			body:        `<html>`,
+			err:         httpError(http.StatusUnauthorized, "text/html", `<html>`),
+		},
+		{
+			status:      http.StatusOK,
+			contentType: "text/plain",
+			body:        `ok`,
+			err:         httpError(http.StatusOK, "text/plain", `ok`),
+		},
+		{
+			status:      http.StatusOK,
+			contentType: MediaTypeJSON,
+			body:        `{"errors":"bad"}`,
+			err:         httpError(http.StatusOK, MediaTypeJSON, `{"errors`),
+		},
+	}
+	for _, tt := range data {
+		tt.test(t)
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	assert := assert.New(t)
+	err := &HTTPError{StatusCode: 401, Status: "401 Unauthorized", Body: []byte(" denied\n")}
+	assert.Equal("unexpected response 401 Unauthorized: denied", err.Error())
+	assert.True(err.ClientError())
+	assert.False(err.ServerError())
+	err = &HTTPError{StatusCode: 502}
+	assert.Equal("unexpected response 502", err.Error())
+	assert.False(err.ClientError())
+	assert.True(err.ServerError())
+}
+
+func TestClientRawGet(t *testing.T) {
+	assert := assert.New(t)
+	var req *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		body, _ = ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", MediaTypeJSON)
+		fmt.Fprint(w, `{"data":{"field":"value"}}`)
+	}))
+	defer srv.Close()
+	out, err := New(Config{
+		Endpoint: srv.URL + "/graphql?key=abc",
+	}).Raw(Raw{
+		Query:         "query Q($v: Int) {\n  field(v: $v)\n}",
+		Variables:     map[string]interface{}{"v": 1},
+		OperationName: "Q",
+		Method:        "get",
+	}, nil)
+	assert.NoError(err)
+	assert.Equal(map[string]interface{}{"field": "value"}, out)
+	assert.Equal(http.MethodGet, req.Method)
+	assert.Equal(url.Values{
+		"key":           []string{"abc"},
+		"query":         []string{"query Q($v: Int) { field(v: $v) }"},
+		"variables":     []string{`{"v":1}`},
+		"operationName": []string{"Q"},
+	}, req.URL.Query())
+	assert.Empty(body)
+	assert.Empty(req.Header.Get("Content-Type"))
+	_, err = New(Config{Endpoint: srv.URL}).Raw(Raw{Method: http.MethodGet}, nil)
+	assert.Error(err)
+}
@@ -1,10 +1,16 @@
 package client
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/http"
 )
 
+// MaxErrorBodySize is a maximum number of bytes of response
+// body kept in HTTPError
+var MaxErrorBodySize = 4096
+
 // Location where error occured, if specified.
 type Location struct {
 	// Line is a number indicating at which line did error occur
@@ -34,3 +40,38 @@ func (g Errors) Error() string {
 	}
 	return string(b)
 }
+
+// HTTPError is returned if remote endpoint responded with
+// a status or a body that is not a valid GraphQL response
+type HTTPError struct {
+	// StatusCode is a http status code of the response
+	StatusCode int
+	// Status is a http status line of the response
+	Status string
+	// Header is a set of http headers of the response
+	Header http.Header
+	// Body is a response body truncated to MaxErrorBodySize bytes
+	Body []byte
+}
+
+// Error formats HTTPError as a status followed by a body
+func (h *HTTPError) Error() string {
+	msg := "unexpected response " + h.Status
+	if h.Status == "" {
+		msg = fmt.Sprintf("unexpected response %d", h.StatusCode)
+	}
+	if body := bytes.TrimSpace(h.Body); len(body) != 0 {
+		msg += ": " + string(body)
+	}
+	return msg
+}
+
+// ClientError returns true if status code indicates client error
+func (h *HTTPError) ClientError() bool {
+	return h.StatusCode >= 400 && h.StatusCode < 500
+}
+
+// ServerError returns true if status code indicates server error
+func (h *HTTPError) ServerError() bool {
+	return h.StatusCode >= 500 && h.StatusCode < 600
+}
@@ -0,0 +1,255 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+)
+
+const (
+	// MediaTypeMultipartMixed is a media type of incremental
+	// delivery response to queries using @defer or @stream
+	MediaTypeMultipartMixed = "multipart/mixed"
+	// incrementalAccept is added to Accept header, announcing
+	// support of incremental delivery
+	incrementalAccept = MediaTypeMultipartMixed + ";deferSpec=20220824;q=0.8"
+)
+
+// Incremental is a result of @defer or @stream directive
+// delivered as a part of incremental response
+type Incremental struct {
+	// Data of deferred fragment, merged into object at Path
+	Data interface{} `json:"data,omitempty"`
+	// Items of streamed list, appended to list at Path
+	// with last element being index of first item
+	Items []interface{} `json:"items,omitempty"`
+	// Path to the deferred or streamed value
+	Path []interface{} `json:"path"`
+	// Label set by directive, if any
+	Label string `json:"label,omitempty"`
+	// Errors raised while resolving this payload
+	Errors Errors `json:"errors,omitempty"`
+}
+
+// Patch is a single payload of incremental response.
+// First payload carries initial data, subsequent
+// payloads carry list of incremental results.
+type Patch struct {
+	// Data is an initial result of the query
+	Data interface{} `json:"data,omitempty"`
+	// Errors is an optional list of errors
+	Errors Errors `json:"errors,omitempty"`
+	// Incremental is a list of deferred and streamed results
+	Incremental []Incremental `json:"incremental,omitempty"`
+	// HasNext is true if more payloads will follow
+	HasNext bool `json:"hasNext"`
+	// Path, Items and Label are set by servers using older
+	// format of incremental delivery, with single result
+	// per payload
+	Path  []interface{} `json:"path,omitempty"`
+	Items []interface{} `json:"items,omitempty"`
+	Label string        `json:"label,omitempty"`
+}
+
+// results returns incremental results of a subsequent payload
+func (p Patch) results() []Incremental {
+	if len(p.Incremental) != 0 || p.Path == nil {
+		return p.Incremental
+	}
+	return []Incremental{{
+		Data:   p.Data,
+		Items:  p.Items,
+		Path:   p.Path,
+		Label:  p.Label,
+		Errors: p.Errors,
+	}}
+}
+
+// incrementalResult is a result merged from incremental payloads
+type incrementalResult struct {
+	data    interface{}
+	errors  Errors
+	initial bool
+}
+
+func (res *incrementalResult) apply(p Patch) error {
+	if !res.initial {
+		res.initial = true
+		res.data = p.Data
+		res.errors = append(res.errors, p.Errors...)
+		return nil
+	}
+	for _, inc := range p.results() {
+		res.errors = append(res.errors, inc.Errors...)
+		var err error
+		switch {
+		case inc.Items != nil:
+			if len(inc.Path) == 0 {
+				return fmt.Errorf("invalid stream path %v", inc.Path)
+			}
+			res.data, err = updatePath(res.data, inc.Path[:len(inc.Path)-1], func(v interface{}) (interface{}, error) {
+				list, ok := v.([]interface{})
+				if v != nil && !ok {
+					return nil, fmt.Errorf("streamed value at %v is not a list", inc.Path)
+				}
+				return append(list, inc.Items...), nil
+			})
+		case inc.Data != nil:
+			res.data, err = updatePath(res.data, inc.Path, func(v interface{}) (interface{}, error) {
+				if _, ok := v.(map[string]interface{}); !ok {
+					return nil, fmt.Errorf("deferred value at %v is not an object", inc.Path)
+				}
+				return mergeData(v, inc.Data), nil
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if len(p.Incremental) != 0 {
+		res.errors = append(res.errors, p.Errors...)
+	}
+	return nil
+}
+
+func pathIndex(v interface{}) (int, bool) {
+	switch vt := v.(type) {
+	case float64:
+		return int(vt), float64(int(vt)) == vt
+	case int:
+		return vt, true
+	case json.Number:
+		i, err := vt.Int64()
+		return int(i), err == nil
+	}
+	return 0, false
+}
+
+// updatePath replaces value at path in node with result of fn
+func updatePath(node interface{}, path []interface{}, fn func(interface{}) (interface{}, error)) (interface{}, error) {
+	if len(path) == 0 {
+		return fn(node)
+	}
+	switch n := node.(type) {
+	case map[string]interface{}:
+		key, ok := path[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid path element %v", path[0])
+		}
+		v, err := updatePath(n[key], path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		n[key] = v
+		return n, nil
+	case []interface{}:
+		i, ok := pathIndex(path[0])
+		if !ok || i < 0 || i >= len(n) {
+			return nil, fmt.Errorf("invalid path element %v", path[0])
+		}
+		v, err := updatePath(n[i], path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = v
+		return n, nil
+	}
+	return nil, fmt.Errorf("path element %v not found", path[0])
+}
+
+// mergeData deep merges src into dst objects
+func mergeData(dst, src interface{}) interface{} {
+	dm, ok := dst.(map[string]interface{})
+	if !ok {
+		return src
+	}
+	sm, ok := src.(map[string]interface{})
+	if !ok {
+		return src
+	}
+	for k, v := range sm {
+		dm[k] = mergeData(dm[k], v)
+	}
+	return dm
+}
+
+// readIncremental reads payloads of multipart/mixed response
+// calling onPatch with each of them
+func readIncremental(body io.Reader, boundary string, onPatch func(Patch) error) error {
+	mr := multipart.NewReader(body, boundary)
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		b, err := ioutil.ReadAll(part)
+		if err != nil {
+			return err
+		}
+		var p Patch
+		if err := json.Unmarshal(b, &p); err != nil {
+			return err
+		}
+		if err := onPatch(p); err != nil {
+			return err
+		}
+		if !p.HasNext {
+			return nil
+		}
+	}
+}
+
+// decodeIncremental decodes multipart/mixed incremental response
+// merging all payloads into data
+func decodeIncremental(resp *http.Response, boundary string, out interface{}, onPatch func(Patch) error) (interface{}, error) {
+	if boundary == "" {
+		boundary = "-"
+	}
+	res := &incrementalResult{}
+	err := readIncremental(resp.Body, boundary, func(p Patch) error {
+		if err := res.apply(p); err != nil {
+			return err
+		}
+		if onPatch != nil {
+			return onPatch(p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	data := res.data
+	if out != nil && data != nil {
+		b, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, out); err != nil {
+			return nil, err
+		}
+		data = out
+	}
+	if len(res.errors) != 0 {
+		return data, res.errors
+	}
+	return data, nil
+}
+
+// RawIncremental executes GraphQL query against GraphQL remote
+// calling onPatch with each payload of incremental response to
+// query using @defer or @stream as it arrives. Response which is
+// not incremental is passed to onPatch as a single payload.
+// Returns data merged from all payloads.
+func (c *Client) RawIncremental(ctx context.Context, r Raw, out interface{}, onPatch func(Patch) error) (interface{}, error) {
+	if c.Persisted {
+		return c.rawPersisted(ctx, r, out, onPatch)
+	}
+	return c.send(ctx, r, out, c.retryable(r), onPatch)
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aexol/test_util"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCaseIncrementalResult struct {
+	patches []string
+	data    string
+	errors  Errors
+	err     func(*assert.Assertions) test_util.ErrorAssertion
+}
+
+func (tt testCaseIncrementalResult) test(t *testing.T) {
+	assert := assert.New(t)
+	if tt.err == nil {
+		tt.err = test_util.NoError
+	}
+	res := &incrementalResult{}
+	var err error
+	for _, p := range tt.patches {
+		var patch Patch
+		assert.NoError(json.Unmarshal([]byte(p), &patch))
+		if err = res.apply(patch); err != nil {
+			break
+		}
+	}
+	tt.err(assert)(err)
+	if err != nil {
+		return
+	}
+	b, _ := json.Marshal(res.data)
+	assert.JSONEq(tt.data, string(b))
+	assert.Equal(tt.errors, res.errors)
+}
+
+func TestIncrementalResult(t *testing.T) {
+	data := []testCaseIncrementalResult{
+		// defer
+		{
+			patches: []string{
+				`{"data":{"user":{"id":"1"}},"hasNext":true}`,
+				`{"incremental":[{"data":{"name":"a","friends":[{"id":"2"}]},"path":["user"],"label":"user"}],"hasNext":true}`,
+				`{"incremental":[{"data":{"name":"b"},"path":["user","friends",0]}],"hasNext":false}`,
+			},
+			data: `{"user":{"id":"1","name":"a","friends":[{"id":"2","name":"b"}]}}`,
+		},
+		// stream
+		{
+			patches: []string{
+				`{"data":{"list":[1]},"hasNext":true}`,
+				`{"incremental":[{"items":[2,3],"path":["list",1]}],"hasNext":true}`,
+				`{"incremental":[{"items":[4],"path":["list",3],"errors":[{"message":"error"}]}],"hasNext":false}`,
+			},
+			data:   `{"list":[1,2,3,4]}`,
+			errors: Errors{{Message: "error"}},
+		},
+		// older format with single result in payload
+		{
+			patches: []string{
+				`{"data":{"user":{"id":"1"}},"hasNext":true}`,
+				`{"data":{"name":"a"},"path":["user"],"hasNext":false}`,
+			},
+			data: `{"user":{"id":"1","name":"a"}}`,
+		},
+		{
+			patches: []string{
+				`{"data":{"user":{"id":"1"}},"hasNext":true}`,
+				`{"incremental":[{"data":{"name":"a"},"path":["other"]}],"hasNext":false}`,
+			},
+			err: test_util.Error,
+		},
+		{
+			patches: []string{
+				`{"data":{"user":{"id":"1"}},"hasNext":true}`,
+				`{"incremental":[{"items":[1],"path":["user","id",0]}],"hasNext":false}`,
+			},
+			err: test_util.Error,
+		},
+	}
+	for _, tt := range data {
+		tt.test(t)
+	}
+}
+
+func TestClientRawIncremental(t *testing.T) {
+	assert := assert.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", `multipart/mixed; boundary="-"; deferSpec=20220824`)
+		fmt.Fprint(w, "\r\n---\r\nContent-Type: application/json; charset=utf-8\r\n\r\n")
+		fmt.Fprint(w, `{"data":{"user":{"id":"1"}},"hasNext":true}`)
+		fmt.Fprint(w, "\r\n---\r\nContent-Type: application/json; charset=utf-8\r\n\r\n")
+		fmt.Fprint(w, `{"incremental":[{"data":{"name":"a"},"path":["user"]}],"hasNext":false}`)
+		fmt.Fprint(w, "\r\n-----\r\n")
+	}))
+	defer srv.Close()
+	cli := New(Config{Endpoint: srv.URL})
+	var patches []Patch
+	data, err := cli.RawIncremental(context.Background(), Raw{Query: "{ user { id ... @defer { name } } }"}, nil, func(p Patch) error {
+		patches = append(patches, p)
+		return nil
+	})
+	assert.NoError(err)
+	assert.Len(patches, 2)
+	assert.Equal(map[string]interface{}{
+		"user": map[string]interface{}{"id": "1", "name": "a"},
+	}, data)
+	var out struct {
+		User struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"user"`
+	}
+	_, err = cli.Raw(Raw{Query: "{ user { id ... @defer { name } } }"}, &out)
+	assert.NoError(err)
+	assert.Equal("a", out.User.Name)
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	// persistedQueryNotFound is returned by server if hash
+	// is not registered
+	persistedQueryNotFound = "PersistedQueryNotFound"
+	// persistedQueryNotSupported is returned by server without
+	// persisted queries support
+	persistedQueryNotSupported = "PersistedQueryNotSupported"
+)
+
+// PersistedQueryCache keeps track of query hashes
+// registered on remote endpoint
+type PersistedQueryCache interface {
+	// Known returns true if hash was registered
+	Known(hash string) bool
+	// Add marks hash as registered
+	Add(hash string)
+}
+
+type memoryPersistedQueryCache struct {
+	mu     sync.Mutex
+	hashes map[string]struct{}
+}
+
+// NewMemoryPersistedQueryCache returns PersistedQueryCache
+// kept in memory
+func NewMemoryPersistedQueryCache() PersistedQueryCache {
+	return &memoryPersistedQueryCache{
+		hashes: make(map[string]struct{}),
+	}
+}
+
+func (m *memoryPersistedQueryCache) Known(hash string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.hashes[hash]
+	return ok
+}
+
+func (m *memoryPersistedQueryCache) Add(hash string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hashes[hash] = struct{}{}
+}
+
+// QueryHash returns sha256 hash of a query, query
+// is sent exactly as it was hashed
+func QueryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// persistedQueryError returns persisted query error code
+// reported by server, if any
+func persistedQueryError(err error) string {
+	var errs Errors
+	switch e := err.(type) {
+	case Errors:
+		errs = e
+	case *HTTPError:
+		// some servers respond to unknown hash
+		// with non 2xx status
+		var body struct {
+			Errors Errors `json:"errors"`
+		}
+		if json.Unmarshal(e.Body, &body) != nil {
+			return ""
+		}
+		errs = body.Errors
+	default:
+		return ""
+	}
+	for _, e := range errs {
+		code, _ := e.Extensions["code"].(string)
+		switch {
+		case e.Message == persistedQueryNotFound || code == "PERSISTED_QUERY_NOT_FOUND":
+			return persistedQueryNotFound
+		case e.Message == persistedQueryNotSupported || code == "PERSISTED_QUERY_NOT_SUPPORTED":
+			return persistedQueryNotSupported
+		}
+	}
+	return ""
+}
+
+// skipPersistedErrors wraps onPatch so that it is not called with
+// persisted query errors, as query is sent again in such case
+func skipPersistedErrors(onPatch func(Patch) error) func(Patch) error {
+	if onPatch == nil {
+		return nil
+	}
+	return func(p Patch) error {
+		if persistedQueryError(p.Errors) != "" {
+			return nil
+		}
+		return onPatch(p)
+	}
+}
+
+// rawPersisted executes query using automatic persisted queries.
+// Query known to be registered is sent as a hash only, if server
+// does not know it, it is sent again with full query. Hash only
+// requests of queries are sent with GET, so that they can be
+// cached, unless request method is set.
+func (c *Client) rawPersisted(ctx context.Context, r Raw, out interface{}, onPatch func(Patch) error) (interface{}, error) {
+	if r.Query == "" {
+		return c.send(ctx, r, out, false, onPatch)
+	}
+	retryable := c.retryable(r)
+	hash := QueryHash(r.Query)
+	ext := make(map[string]interface{}, len(r.Extensions)+1)
+	for k, v := range r.Extensions {
+		ext[k] = v
+	}
+	ext["persistedQuery"] = map[string]interface{}{
+		"version":    1,
+		"sha256Hash": hash,
+	}
+	full := r
+	full.Extensions = ext
+	if c.PersistedCache.Known(hash) {
+		hashOnly := full
+		hashOnly.Query = ""
+		// mutations cannot be sent with GET
+		if hashOnly.Method == "" && operationType(r) == ast.OperationTypeQuery {
+			hashOnly.Method = http.MethodGet
+		}
+		data, err := c.send(ctx, hashOnly, out, retryable, skipPersistedErrors(onPatch))
+		switch persistedQueryError(err) {
+		case persistedQueryNotFound:
+		case persistedQueryNotSupported:
+			return c.send(ctx, r, out, retryable, onPatch)
+		default:
+			return data, err
+		}
+	}
+	data, err := c.send(ctx, full, out, retryable, skipPersistedErrors(onPatch))
+	switch persistedQueryError(err) {
+	case persistedQueryNotSupported:
+		return c.send(ctx, r, out, retryable, onPatch)
+	case "":
+		if _, ok := err.(Errors); ok || err == nil {
+			// query reached GraphQL server, so
+			// it is registered now
+			c.PersistedCache.Add(hash)
+		}
+	}
+	return data, err
+}
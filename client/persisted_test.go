@@ -0,0 +1,147 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// apqTestServer registers queries sent along with hash
+type apqTestServer struct {
+	registered map[string]bool
+	requests   []Raw
+	methods    []string
+}
+
+func (a *apqTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req Raw
+	if r.Method == http.MethodGet {
+		q := r.URL.Query()
+		req.Query = q.Get("query")
+		json.Unmarshal([]byte(q.Get("extensions")), &req.Extensions) // nolint: errcheck
+	} else {
+		b, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(b, &req) // nolint: errcheck
+	}
+	a.requests = append(a.requests, req)
+	a.methods = append(a.methods, r.Method)
+	pq, _ := req.Extensions["persistedQuery"].(map[string]interface{})
+	hash, _ := pq["sha256Hash"].(string)
+	if req.Query == "" {
+		if !a.registered[hash] {
+			fmt.Fprint(w, `{"errors":[{"message":"PersistedQueryNotFound"}]}`)
+			return
+		}
+	} else if hash != "" {
+		a.registered[hash] = true
+	}
+	fmt.Fprint(w, `{"data":{"field":"value"}}`)
+}
+
+func TestQueryHash(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(
+		"06626312c84a7d495de5d55ab48a6baf9482dd9a943c0a0a738fd04c658b3654",
+		QueryHash("{ field }"),
+	)
+	// query is hashed as it is written
+	assert.NotEqual(QueryHash("{ field }"), QueryHash("  {   field }  "))
+}
+
+func TestClientPersisted(t *testing.T) {
+	assert := assert.New(t)
+	query := "{\n  field # comment\n}"
+	tests := map[string][]string{
+		"":              {http.MethodPost, http.MethodGet, http.MethodGet, http.MethodPost},
+		http.MethodPost: {http.MethodPost, http.MethodPost, http.MethodPost, http.MethodPost},
+		http.MethodGet:  {http.MethodGet, http.MethodGet, http.MethodGet, http.MethodGet},
+	}
+	for method, methods := range tests {
+		srv := &apqTestServer{registered: make(map[string]bool)}
+		ts := httptest.NewServer(srv)
+		cache := NewMemoryPersistedQueryCache()
+		cli := New(Config{
+			Endpoint:       ts.URL,
+			Persisted:      true,
+			PersistedCache: cache,
+		})
+		r := Raw{Query: query, Method: method}
+		hash := QueryHash(r.Query)
+		// unknown query is sent in full with hash
+		data, err := cli.Raw(r, nil)
+		assert.NoError(err)
+		assert.Equal(map[string]interface{}{"field": "value"}, data)
+		assert.True(cache.Known(hash))
+		// known query is sent as hash only
+		_, err = cli.Raw(r, nil)
+		assert.NoError(err)
+		// server forgot query, it is sent again
+		srv.registered = make(map[string]bool)
+		_, err = cli.Raw(r, nil)
+		assert.NoError(err)
+		assert.Len(srv.requests, 4, method)
+		assert.Equal(methods, srv.methods, method)
+		for i, q := range []string{query, "", "", query} {
+			assert.Equal(q, srv.requests[i].Query, method)
+			assert.Equal(
+				map[string]interface{}{"version": float64(1), "sha256Hash": hash},
+				srv.requests[i].Extensions["persistedQuery"],
+				method,
+			)
+		}
+		ts.Close()
+	}
+}
+
+func TestClientPersistedMutation(t *testing.T) {
+	assert := assert.New(t)
+	query := "mutation { field }"
+	srv := &apqTestServer{registered: map[string]bool{QueryHash(query): true}}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	cache := NewMemoryPersistedQueryCache()
+	cache.Add(QueryHash(query))
+	_, err := New(Config{
+		Endpoint:       ts.URL,
+		Persisted:      true,
+		PersistedCache: cache,
+	}).Raw(Raw{Query: query}, nil)
+	assert.NoError(err)
+	// known mutation is sent as hash only, but never with GET
+	assert.Equal([]string{http.MethodPost}, srv.methods)
+	assert.Equal("", srv.requests[0].Query)
+}
+
+func TestClientPersistedNotSupported(t *testing.T) {
+	assert := assert.New(t)
+	var requests []Raw
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Raw
+		b, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(b, &req) // nolint: errcheck
+		requests = append(requests, req)
+		if req.Extensions != nil {
+			w.Header().Set("Content-Type", MediaTypeJSON)
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"errors":[{"message":"PersistedQueryNotSupported"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"field":"value"}}`)
+	}))
+	defer ts.Close()
+	cache := NewMemoryPersistedQueryCache()
+	_, err := New(Config{
+		Endpoint:       ts.URL,
+		Persisted:      true,
+		PersistedCache: cache,
+	}).Raw(Raw{Query: "{ field }"}, nil)
+	assert.NoError(err)
+	assert.Len(requests, 2)
+	assert.Nil(requests[1].Extensions)
+	assert.False(cache.Known(QueryHash("{ field }")))
+}
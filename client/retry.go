@@ -0,0 +1,214 @@
+package client
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+const (
+	defaultMinBackoff = 500 * time.Millisecond
+	defaultMaxBackoff = 30 * time.Second
+)
+
+var (
+	// DefaultRetryableStatus is a list of http status codes
+	// retried if RetryPolicy does not define its own
+	DefaultRetryableStatus = []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	}
+
+	// randInt63n is used for backoff jitter
+	randInt63n = rand.Int63n
+)
+
+// RetryPolicy defines how requests failed with transient
+// errors are retried. By default only queries are retried,
+// as mutations are not idempotent.
+type RetryPolicy struct {
+	// MaxAttempts is a maximum number of attempts,
+	// including the first one
+	MaxAttempts int
+	// MinBackoff is a delay before the first retry, doubled
+	// with each attempt. Defaults to 500ms
+	MinBackoff time.Duration
+	// MaxBackoff caps delay between attempts. Defaults to 30s
+	MaxBackoff time.Duration
+	// MaxElapsed limits total time spent on request
+	// including retries, no limit if zero
+	MaxElapsed time.Duration
+	// RetryableStatus is a list of http status codes on which
+	// request is retried, defaults to DefaultRetryableStatus
+	RetryableStatus []int
+	// RetryMutations allows retrying mutations
+	RetryMutations bool
+}
+
+// operationType returns type of operation that would
+// be executed by the request or empty string if it
+// cannot be determined
+func operationType(r Raw) string {
+	doc, err := parser.Parse(parser.ParseParams{Source: r.Query})
+	if err != nil {
+		return ""
+	}
+	var op *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		if o, ok := def.(*ast.OperationDefinition); ok {
+			if r.OperationName == "" && op != nil {
+				// ambiguous operation
+				return ""
+			}
+			if r.OperationName == "" || (o.Name != nil && o.Name.Value == r.OperationName) {
+				op = o
+			}
+		}
+	}
+	if op == nil {
+		return ""
+	}
+	return op.Operation
+}
+
+// idempotent returns true if the request can be safely retried
+func (p *RetryPolicy) idempotent(r Raw) bool {
+	switch operationType(r) {
+	case ast.OperationTypeQuery:
+		return true
+	case ast.OperationTypeMutation:
+		return p.RetryMutations
+	}
+	return false
+}
+
+func (p *RetryPolicy) retryableStatus(code int) bool {
+	codes := p.RetryableStatus
+	if codes == nil {
+		codes = DefaultRetryableStatus
+	}
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns exponential delay with jitter before next attempt
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	min, max := p.MinBackoff, p.MaxBackoff
+	if min <= 0 {
+		min = defaultMinBackoff
+	}
+	if max <= 0 {
+		max = defaultMaxBackoff
+	}
+	d := min
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	// equal jitter, keep at least half of delay
+	half := int64(d / 2)
+	return time.Duration(half + randInt63n(half+1))
+}
+
+// retryAfter parses Retry-After header, which is either
+// a number of seconds or http date
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// next returns delay before next attempt, or false
+// if request should not be retried
+func (p *RetryPolicy) next(attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts {
+		return 0, false
+	}
+	if err != nil {
+		return p.backoff(attempt), true
+	}
+	if !p.retryableStatus(resp.StatusCode) {
+		return 0, false
+	}
+	if d, ok := retryAfter(resp, time.Now()); ok {
+		return d, true
+	}
+	return p.backoff(attempt), true
+}
+
+func drain(resp *http.Response) {
+	io.Copy(ioutil.Discard, resp.Body) // nolint: errcheck
+	resp.Body.Close()                  // nolint: errcheck
+}
+
+// retryable returns true if request for query can be retried
+func (c *Client) retryable(r Raw) bool {
+	return c.Retry != nil && c.Retry.idempotent(r)
+}
+
+// do sends request built by newRequest retrying it according
+// to client retry policy if retryable is true
+func (c *Client) do(ctx context.Context, newRequest func() (*http.Request, error), retryable bool) (*http.Response, error) {
+	p := c.Retry
+	if !retryable {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		return c.Do(req)
+	}
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.Do(req)
+		if ctx.Err() != nil {
+			return resp, err
+		}
+		delay, retry := p.next(attempt, resp, err)
+		if retry && p.MaxElapsed > 0 && time.Since(start)+delay > p.MaxElapsed {
+			retry = false
+		}
+		if !retry {
+			return resp, err
+		}
+		if resp != nil {
+			drain(resp)
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
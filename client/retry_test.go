@@ -0,0 +1,198 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aexol/test_util"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCaseOperationType struct {
+	in  Raw
+	out string
+}
+
+func TestOperationType(t *testing.T) {
+	assert := assert.New(t)
+	data := []testCaseOperationType{
+		{in: Raw{Query: "{ field }"}, out: "query"},
+		{in: Raw{Query: "query { field }"}, out: "query"},
+		{in: Raw{Query: "mutation { field }"}, out: "mutation"},
+		{in: Raw{Query: "subscription { field }"}, out: "subscription"},
+		{in: Raw{Query: "query A { field } mutation B { field }"}, out: ""},
+		{in: Raw{Query: "query A { field } mutation B { field }", OperationName: "B"}, out: "mutation"},
+		{in: Raw{Query: "query A { field }", OperationName: "C"}, out: ""},
+		{in: Raw{Query: "{ field"}, out: ""},
+	}
+	for _, tt := range data {
+		assert.Equal(tt.out, operationType(tt.in), "operation type of %s", tt.in.Query)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	resp := &http.Response{Header: make(http.Header)}
+	_, ok := retryAfter(resp, now)
+	assert.False(ok)
+	resp.Header.Set("Retry-After", "3")
+	d, ok := retryAfter(resp, now)
+	assert.True(ok)
+	assert.Equal(3*time.Second, d)
+	resp.Header.Set("Retry-After", now.Add(time.Minute).Format(http.TimeFormat))
+	d, ok = retryAfter(resp, now)
+	assert.True(ok)
+	assert.Equal(time.Minute, d)
+	resp.Header.Set("Retry-After", "soon")
+	_, ok = retryAfter(resp, now)
+	assert.False(ok)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	assert := assert.New(t)
+	old := randInt63n
+	randInt63n = func(n int64) int64 { return n - 1 }
+	defer func() {
+		randInt63n = old
+	}()
+	p := &RetryPolicy{
+		MinBackoff: time.Second,
+		MaxBackoff: 5 * time.Second,
+	}
+	assert.Equal(time.Second, p.backoff(1))
+	assert.Equal(2*time.Second, p.backoff(2))
+	assert.Equal(4*time.Second, p.backoff(3))
+	assert.Equal(5*time.Second, p.backoff(4))
+	assert.Equal(5*time.Second, p.backoff(10))
+}
+
+type testCaseClientRetry struct {
+	policy   *RetryPolicy
+	query    string
+	status   []int
+	attempts int
+	err      func(assert *assert.Assertions) test_util.ErrorAssertion
+}
+
+func (tt testCaseClientRetry) test(t *testing.T) {
+	assert := assert.New(t)
+	if tt.err == nil {
+		tt.err = test_util.NoError
+	}
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := http.StatusOK
+		if attempts < len(tt.status) {
+			status = tt.status[attempts]
+		}
+		attempts++
+		if status != http.StatusOK {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(status)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"field":"value"}}`)
+	}))
+	defer srv.Close()
+	_, err := New(Config{
+		Endpoint: srv.URL,
+		Retry:    tt.policy,
+	}).RawContext(context.Background(), Raw{Query: tt.query}, nil)
+	tt.err(assert)(err)
+	assert.Equal(tt.attempts, attempts)
+}
+
+func TestClientRetry(t *testing.T) {
+	data := []testCaseClientRetry{
+		{
+			policy:   &RetryPolicy{MaxAttempts: 3},
+			query:    "{ field }",
+			status:   []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+			attempts: 3,
+		},
+		{
+			policy:   &RetryPolicy{MaxAttempts: 2},
+			query:    "{ field }",
+			status:   []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+			attempts: 2,
+			err:      test_util.Error,
+		},
+		{
+			policy:   &RetryPolicy{MaxAttempts: 3},
+			query:    "mutation { field }",
+			status:   []int{http.StatusBadGateway},
+			attempts: 1,
+			err:      test_util.Error,
+		},
+		{
+			policy:   &RetryPolicy{MaxAttempts: 3, RetryMutations: true},
+			query:    "mutation { field }",
+			status:   []int{http.StatusBadGateway},
+			attempts: 2,
+		},
+		{
+			policy:   &RetryPolicy{MaxAttempts: 3},
+			query:    "{ field }",
+			status:   []int{http.StatusInternalServerError},
+			attempts: 1,
+			err:      test_util.Error,
+		},
+		{
+			policy:   &RetryPolicy{MaxAttempts: 3, RetryableStatus: []int{http.StatusInternalServerError}},
+			query:    "{ field }",
+			status:   []int{http.StatusInternalServerError},
+			attempts: 2,
+		},
+		{
+			query:    "{ field }",
+			status:   []int{http.StatusBadGateway},
+			attempts: 1,
+			err:      test_util.Error,
+		},
+	}
+	for _, tt := range data {
+		tt.test(t)
+	}
+}
+
+func TestClientRetryMaxElapsed(t *testing.T) {
+	assert := assert.New(t)
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	_, err := New(Config{
+		Endpoint: srv.URL,
+		Retry: &RetryPolicy{
+			MaxAttempts: 5,
+			MaxElapsed:  time.Second,
+		},
+	}).Raw(Raw{Query: "{ field }"}, nil)
+	assert.Error(err)
+	assert.Equal(1, attempts)
+}
+
+func TestClientRetryNetworkError(t *testing.T) {
+	assert := assert.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	endpoint := srv.URL
+	srv.Close()
+	start := time.Now()
+	_, err := New(Config{
+		Endpoint: endpoint,
+		Retry: &RetryPolicy{
+			MaxAttempts: 3,
+			MinBackoff:  10 * time.Millisecond,
+		},
+	}).Raw(Raw{Query: "{ field }"}, nil)
+	assert.Error(err)
+	assert.True(time.Since(start) >= 10*time.Millisecond)
+}
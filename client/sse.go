@@ -0,0 +1,216 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sseContentType = "text/event-stream"
+	// number of consecutive reconnects without receiving
+	// any event after which SSE stream gives up
+	sseMaxReconnects = 5
+)
+
+var (
+	sseDefaultRetry = time.Second
+)
+
+type sseEvent struct {
+	id    string
+	event string
+	data  []byte
+	retry time.Duration
+}
+
+// sseReader parses text/event-stream as defined by
+// https://html.spec.whatwg.org/multipage/server-sent-events.html
+type sseReader struct {
+	r *bufio.Reader
+}
+
+func (s *sseReader) next() (sseEvent, error) {
+	var ev sseEvent
+	var data [][]byte
+	hasField := false
+	for {
+		line, err := s.r.ReadBytes('\n')
+		if err != nil {
+			// incomplete event at the end of stream
+			// is discarded
+			return ev, err
+		}
+		line = bytes.TrimRight(line, "\r\n")
+		if len(line) == 0 {
+			if !hasField {
+				continue
+			}
+			ev.data = bytes.Join(data, []byte("\n"))
+			return ev, nil
+		}
+		if line[0] == ':' {
+			// comment
+			continue
+		}
+		hasField = true
+		field, value := line, []byte{}
+		if i := bytes.IndexByte(line, ':'); i != -1 {
+			field, value = line[:i], line[i+1:]
+			value = bytes.TrimPrefix(value, []byte(" "))
+		}
+		switch string(field) {
+		case "event":
+			ev.event = string(value)
+		case "data":
+			data = append(data, value)
+		case "id":
+			ev.id = string(value)
+		case "retry":
+			if ms, err := strconv.Atoi(string(value)); err == nil {
+				ev.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+type sseStream struct {
+	cli         *Client
+	raw         Raw
+	ctx         context.Context
+	lastEventID string
+	retry       time.Duration
+}
+
+func (s *sseStream) connect() (io.ReadCloser, error) {
+	req, err := s.cli.newRequest(s.ctx, s.raw)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", sseContentType)
+	if s.lastEventID != "" {
+		req.Header.Set("Last-Event-ID", s.lastEventID)
+	}
+	resp, err := s.cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close() // nolint: errcheck
+		return nil, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, sseContentType) {
+		resp.Body.Close() // nolint: errcheck
+		return nil, fmt.Errorf("unexpected response content type %s", ct)
+	}
+	return resp.Body, nil
+}
+
+// consume reads events until stream ends, returns true
+// if complete event was received
+func (s *sseStream) consume(sub *Subscription, body io.Reader, received *bool) (bool, error) {
+	rd := &sseReader{r: bufio.NewReader(body)}
+	for {
+		ev, err := rd.next()
+		if err != nil {
+			return false, err
+		}
+		if ev.id != "" {
+			s.lastEventID = ev.id
+		}
+		if ev.retry != 0 {
+			s.retry = ev.retry
+		}
+		switch ev.event {
+		case "next", "":
+			var resp Response
+			if err := json.Unmarshal(ev.data, &resp); err != nil {
+				return false, err
+			}
+			*received = true
+			if !sub.send(resp) {
+				return true, nil
+			}
+		case "complete":
+			return true, nil
+		}
+	}
+}
+
+func (s *sseStream) run(sub *Subscription, body io.ReadCloser) {
+	var err error
+	defer func() {
+		select {
+		case <-sub.done:
+			err = nil
+		default:
+		}
+		sub.finish(err)
+	}()
+	for reconnects := 0; ; {
+		// body is nil if reconnect failed
+		if body != nil {
+			var complete, received bool
+			complete, err = s.consume(sub, body, &received)
+			body.Close() // nolint: errcheck
+			if complete {
+				err = nil
+				return
+			}
+			if received {
+				reconnects = 0
+			}
+		}
+		// stream ended before complete event or could
+		// not be reopened, reconnect honouring Last-Event-ID
+		if reconnects++; reconnects > sseMaxReconnects {
+			return
+		}
+		select {
+		case <-sub.done:
+			return
+		case <-time.After(s.retry):
+		}
+		body, err = s.connect()
+	}
+}
+
+// SubscribeSSE executes GraphQL subscription against remote endpoint
+// using Server-Sent Events in distinct connections mode of
+// GraphQL over SSE protocol. If the stream is interrupted before
+// the operation completes, client reconnects sending last
+// received event id in Last-Event-ID header.
+func (c *Client) SubscribeSSE(r Raw) (*Subscription, error) {
+	return c.SubscribeSSEContext(context.Background(), r)
+}
+
+// SubscribeSSEContext executes GraphQL subscription over
+// Server-Sent Events closing it when ctx is done.
+func (c *Client) SubscribeSSEContext(ctx context.Context, r Raw) (*Subscription, error) {
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	s := &sseStream{
+		cli:   c,
+		raw:   r,
+		ctx:   ctx,
+		retry: sseDefaultRetry,
+	}
+	body, err := s.connect()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	sub := newSubscription(parent, func(bool) error {
+		cancel()
+		return nil
+	})
+	go s.run(sub, body)
+	return sub, nil
+}
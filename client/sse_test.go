@@ -0,0 +1,159 @@
+package client
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCaseSSEReader struct {
+	in  string
+	out []sseEvent
+}
+
+func (tt testCaseSSEReader) test(t *testing.T) {
+	assert := assert.New(t)
+	rd := &sseReader{r: bufio.NewReader(strings.NewReader(tt.in))}
+	var out []sseEvent
+	for {
+		ev, err := rd.next()
+		if err != nil {
+			break
+		}
+		out = append(out, ev)
+	}
+	assert.Equal(tt.out, out)
+}
+
+func TestSSEReader(t *testing.T) {
+	data := []testCaseSSEReader{
+		{
+			in: "event: next\ndata: {\"data\":{}}\n\nevent: complete\ndata:\n\n",
+			out: []sseEvent{
+				{event: "next", data: []byte(`{"data":{}}`)},
+				{event: "complete"},
+			},
+		},
+		{
+			in: ": keep-alive\r\n\r\nid: 5\r\nretry: 100\r\nevent: next\r\ndata: {\"data\":\r\ndata: {}}\r\n\r\n",
+			out: []sseEvent{
+				{id: "5", retry: 100 * time.Millisecond, event: "next", data: []byte("{\"data\":\n{}}")},
+			},
+		},
+		{
+			in:  "event: next\ndata: 1\n",
+			out: nil,
+		},
+	}
+	for _, tt := range data {
+		tt.test(t)
+	}
+}
+
+func TestClientSubscribeSSE(t *testing.T) {
+	assert := assert.New(t)
+	oldRetry := sseDefaultRetry
+	sseDefaultRetry = time.Millisecond
+	defer func() {
+		sseDefaultRetry = oldRetry
+	}()
+	var lastEventIDs []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		if r.Header.Get("Accept") != sseContentType || r.Method != "POST" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", sseContentType)
+		if len(lastEventIDs) == 1 {
+			// drop connection before complete
+			fmt.Fprint(w, "id: 1\nevent: next\ndata: {\"data\":{\"tick\":1}}\n\n")
+			return
+		}
+		fmt.Fprint(w, "id: 2\nevent: next\ndata: {\"data\":{\"tick\":2}}\n\nevent: complete\ndata:\n\n")
+	}))
+	defer srv.Close()
+	sub, err := New(Config{Endpoint: srv.URL}).SubscribeSSE(Raw{Query: "subscription { tick }"})
+	assert.NoError(err)
+	var out []Response
+	for resp := range sub.Events {
+		out = append(out, resp)
+	}
+	assert.NoError(sub.Err())
+	assert.NoError(sub.Close())
+	assert.Equal([]Response{
+		Response{Data: map[string]interface{}{"tick": float64(1)}},
+		Response{Data: map[string]interface{}{"tick": float64(2)}},
+	}, out)
+	assert.Equal([]string{"", "1"}, lastEventIDs)
+}
+
+func TestClientSubscribeSSEReconnectErrors(t *testing.T) {
+	assert := assert.New(t)
+	oldRetry := sseDefaultRetry
+	sseDefaultRetry = time.Millisecond
+	defer func() {
+		sseDefaultRetry = oldRetry
+	}()
+	for _, fail := range []int{2, sseMaxReconnects + 1} {
+		requests := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			switch {
+			case requests == 1:
+				w.Header().Set("Content-Type", sseContentType)
+				fmt.Fprint(w, "id: 1\nevent: next\ndata: {\"data\":{\"tick\":1}}\n\n")
+			case requests <= fail:
+				// temporary failure of reconnect
+				w.WriteHeader(http.StatusServiceUnavailable)
+			default:
+				w.Header().Set("Content-Type", sseContentType)
+				fmt.Fprint(w, "id: 2\nevent: next\ndata: {\"data\":{\"tick\":2}}\n\nevent: complete\ndata:\n\n")
+			}
+		}))
+		sub, err := New(Config{Endpoint: srv.URL}).SubscribeSSE(Raw{Query: "subscription { tick }"})
+		if !assert.NoError(err) {
+			srv.Close()
+			return
+		}
+		var out []Response
+		for resp := range sub.Events {
+			out = append(out, resp)
+		}
+		if fail <= sseMaxReconnects {
+			assert.NoError(sub.Err())
+			assert.Len(out, 2)
+		} else {
+			// gives up after sseMaxReconnects failed reconnects
+			assert.Error(sub.Err())
+			assert.Len(out, 1)
+			assert.Equal(sseMaxReconnects+1, requests)
+		}
+		srv.Close()
+	}
+}
+
+func TestClientSubscribeSSEErrors(t *testing.T) {
+	assert := assert.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/json" {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, "{}")
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+	_, err := New(Config{Endpoint: srv.URL}).SubscribeSSE(Raw{Query: "subscription { tick }"})
+	assert.Error(err)
+	_, err = New(Config{Endpoint: srv.URL + "/json"}).SubscribeSSE(Raw{Query: "subscription { tick }"})
+	assert.Error(err)
+	_, err = New(Config{}).SubscribeSSE(Raw{Query: "subscription { tick }"})
+	assert.Error(err)
+}
@@ -0,0 +1,362 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// ProtocolGraphQLTransportWS is a websocket subprotocol
+	// defined by graphql-ws library
+	ProtocolGraphQLTransportWS = "graphql-transport-ws"
+	// ProtocolGraphQLWS is a legacy websocket subprotocol
+	// defined by subscriptions-transport-ws library
+	ProtocolGraphQLWS = "graphql-ws"
+
+	subscriptionID   = "1"
+	handshakeTimeout = 45 * time.Second
+	closeWait        = time.Second
+)
+
+// Subscription is a stream of responses pushed
+// by remote endpoint for a single operation
+type Subscription struct {
+	// Events receives each result pushed by remote endpoint.
+	// Channel is closed when the operation completes, fails
+	// or subscription is closed.
+	Events <-chan Response
+
+	events  chan Response
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+	err     error
+	stop    func(active bool) error
+	// ctxErr is set if subscription was closed
+	// because its context was done
+	ctxErr error
+	mu     sync.Mutex
+}
+
+func newSubscription(ctx context.Context, stop func(active bool) error) *Subscription {
+	events := make(chan Response)
+	sub := &Subscription{
+		Events:  events,
+		events:  events,
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+		stop:    stop,
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			sub.mu.Lock()
+			sub.ctxErr = ctx.Err()
+			sub.mu.Unlock()
+			sub.Close() // nolint: errcheck
+		case <-sub.stopped:
+		}
+	}()
+	return sub
+}
+
+// send pushes response to the consumer, returns false
+// if subscription was closed in the meantime
+func (s *Subscription) send(r Response) bool {
+	select {
+	case s.events <- r:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// finish must be called exactly once by the goroutine
+// reading from remote endpoint
+func (s *Subscription) finish(err error) {
+	s.err = err
+	close(s.events)
+	close(s.stopped)
+}
+
+// Err returns an error that terminated subscription, if any.
+// Only valid after Events channel was closed.
+func (s *Subscription) Err() error {
+	select {
+	case <-s.stopped:
+		if s.err != nil {
+			return s.err
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.ctxErr
+	default:
+		return nil
+	}
+}
+
+// Close stops the subscription and releases underlying connection
+func (s *Subscription) Close() (err error) {
+	s.once.Do(func() {
+		active := true
+		select {
+		case <-s.stopped:
+			active = false
+		default:
+		}
+		close(s.done)
+		if s.stop != nil {
+			err = s.stop(active)
+		}
+		<-s.stopped
+	})
+	return
+}
+
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+type wsConn struct {
+	*websocket.Conn
+	protocol string
+	mu       sync.Mutex
+}
+
+func (w *wsConn) send(typ, id string, payload interface{}) error {
+	msg := wsMessage{
+		ID:   id,
+		Type: typ,
+	}
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		msg.Payload = b
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.WriteJSON(msg)
+}
+
+func (w *wsConn) legacy() bool {
+	return w.protocol == ProtocolGraphQLWS
+}
+
+// terminate stops the operation if it is still active
+// and closes the connection
+func (w *wsConn) terminate(active bool) error {
+	var err error
+	switch {
+	case active && w.legacy():
+		err = w.send("stop", subscriptionID, nil)
+	case active:
+		err = w.send("complete", subscriptionID, nil)
+	}
+	if w.legacy() {
+		w.send("connection_terminate", "", nil) // nolint: errcheck
+	}
+	w.mu.Lock()
+	w.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(closeWait),
+	) // nolint: errcheck
+	w.mu.Unlock()
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func wsEndpoint(endpoint string) (string, error) {
+	if endpoint == "" {
+		return "", errors.New("endpoint cannot be empty")
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	case "ws", "wss":
+	default:
+		return "", fmt.Errorf("unsupported endpoint scheme %s", u.Scheme)
+	}
+	return u.String(), nil
+}
+
+// decodeErrors decodes error payload which is a list of errors
+// in graphql-transport-ws and a single error in legacy protocol
+func decodeErrors(payload json.RawMessage) Errors {
+	var errs Errors
+	if err := json.Unmarshal(payload, &errs); err == nil {
+		return errs
+	}
+	var single Error
+	if err := json.Unmarshal(payload, &single); err == nil && single.Message != "" {
+		return Errors{single}
+	}
+	return Errors{Error{Message: string(payload)}}
+}
+
+// read connection messages until connection is acknowledged,
+// waiting at most until ctx is done or handshakeTimeout passes
+func (w *wsConn) waitAck(ctx context.Context) error {
+	deadline := time.Now().Add(handshakeTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := w.SetReadDeadline(deadline); err != nil {
+		return err
+	}
+	// unblock read if ctx is cancelled
+	acked := make(chan struct{})
+	defer close(acked)
+	go func() {
+		select {
+		case <-ctx.Done():
+			w.Close() // nolint: errcheck
+		case <-acked:
+		}
+	}()
+	for {
+		var msg wsMessage
+		if err := w.ReadJSON(&msg); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		switch msg.Type {
+		case "connection_ack":
+			return w.SetReadDeadline(time.Time{})
+		case "ping":
+			if err := w.send("pong", "", nil); err != nil {
+				return err
+			}
+		case "ka", "pong":
+		case "connection_error":
+			return decodeErrors(msg.Payload)
+		default:
+			return fmt.Errorf("unexpected message %s before connection_ack", msg.Type)
+		}
+	}
+}
+
+func (w *wsConn) read(sub *Subscription) {
+	var err error
+	defer func() {
+		sub.finish(err)
+	}()
+	for {
+		var msg wsMessage
+		if err = w.ReadJSON(&msg); err != nil {
+			select {
+			case <-sub.done:
+				// connection closed by client
+				err = nil
+			default:
+			}
+			return
+		}
+		switch msg.Type {
+		case "next", "data":
+			var resp Response
+			if err = json.Unmarshal(msg.Payload, &resp); err != nil {
+				return
+			}
+			if !sub.send(resp) {
+				return
+			}
+		case "error":
+			sub.send(Response{Errors: decodeErrors(msg.Payload)})
+			return
+		case "complete":
+			return
+		case "ping":
+			if err = w.send("pong", "", nil); err != nil {
+				return
+			}
+		case "ka", "pong", "connection_ack":
+		case "connection_error":
+			err = decodeErrors(msg.Payload)
+			return
+		}
+	}
+}
+
+// Subscribe executes GraphQL subscription against remote endpoint
+// over websocket. Both graphql-transport-ws and legacy
+// subscriptions-transport-ws protocols are supported, protocol
+// is negotiated with the server.
+func (c *Client) Subscribe(r Raw) (*Subscription, error) {
+	return c.SubscribeContext(context.Background(), r)
+}
+
+// SubscribeContext executes GraphQL subscription over websocket
+// closing it when ctx is done.
+func (c *Client) SubscribeContext(ctx context.Context, r Raw) (*Subscription, error) {
+	if r.Query == "" {
+		return nil, errors.New("query cannot be empty")
+	}
+	endpoint, err := wsEndpoint(c.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: handshakeTimeout,
+		Subprotocols:     []string{ProtocolGraphQLTransportWS, ProtocolGraphQLWS},
+		Jar:              c.Jar,
+		NetDialContext:   c.dialer().DialContext,
+	}
+	conn, resp, err := dialer.DialContext(ctx, endpoint, r.Header)
+	if resp != nil && resp.Body != nil {
+		resp.Body.Close() // nolint: errcheck
+	}
+	if err != nil {
+		return nil, err
+	}
+	ws := &wsConn{
+		Conn:     conn,
+		protocol: conn.Subprotocol(),
+	}
+	if ws.protocol == "" {
+		ws.protocol = ProtocolGraphQLTransportWS
+	}
+	var initPayload interface{}
+	if c.InitPayload != nil {
+		initPayload = c.InitPayload
+	}
+	start := "subscribe"
+	if ws.legacy() {
+		start = "start"
+	}
+	if err = ws.send("connection_init", "", initPayload); err == nil {
+		if err = ws.waitAck(ctx); err == nil {
+			err = ws.send(start, subscriptionID, r)
+		}
+	}
+	if err != nil {
+		conn.Close() // nolint: errcheck
+		return nil, err
+	}
+	sub := newSubscription(ctx, ws.terminate)
+	go ws.read(sub)
+	return sub, nil
+}
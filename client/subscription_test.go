@@ -0,0 +1,279 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aexol/test_util"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+type wsTestServer struct {
+	protocols   []string
+	initPayload map[string]interface{}
+	start       wsMessage
+	events      []interface{}
+	errPayload  interface{}
+	ping        bool
+	pong        bool
+	hold        bool
+}
+
+func (s *wsTestServer) write(conn *websocket.Conn, typ string, payload interface{}) error {
+	msg := wsMessage{Type: typ, ID: s.start.ID}
+	if payload != nil {
+		b, _ := json.Marshal(payload)
+		msg.Payload = b
+	}
+	return conn.WriteJSON(msg)
+}
+
+func (s *wsTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{Subprotocols: s.protocols}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	var msg wsMessage
+	if err := conn.ReadJSON(&msg); err != nil || msg.Type != "connection_init" {
+		return
+	}
+	if len(msg.Payload) != 0 {
+		json.Unmarshal(msg.Payload, &s.initPayload)
+	}
+	if s.ping {
+		s.write(conn, "ping", nil)
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		s.pong = msg.Type == "pong"
+	}
+	s.write(conn, "connection_ack", nil)
+	if err := conn.ReadJSON(&s.start); err != nil {
+		return
+	}
+	next := "next"
+	if conn.Subprotocol() == ProtocolGraphQLWS {
+		next = "data"
+		s.write(conn, "ka", nil)
+	}
+	for _, e := range s.events {
+		s.write(conn, next, e)
+	}
+	if s.hold {
+		// wait for client to stop subscription
+		conn.ReadJSON(&msg)
+		return
+	}
+	if s.errPayload != nil {
+		s.write(conn, "error", s.errPayload)
+		return
+	}
+	s.write(conn, "complete", nil)
+	conn.ReadJSON(&msg)
+}
+
+type testCaseSubscribe struct {
+	server      *wsTestServer
+	initPayload map[string]interface{}
+	startType   string
+	out         []Response
+	err         func(assert *assert.Assertions) test_util.ErrorAssertion
+}
+
+func (tt testCaseSubscribe) test(t *testing.T) {
+	assert := assert.New(t)
+	if tt.err == nil {
+		tt.err = test_util.NoError
+	}
+	srv := httptest.NewServer(tt.server)
+	defer srv.Close()
+	cli := New(Config{
+		Endpoint:    srv.URL,
+		InitPayload: tt.initPayload,
+	})
+	sub, err := cli.Subscribe(Raw{Query: "subscription { field }"})
+	assert.NoError(err)
+	var out []Response
+	for resp := range sub.Events {
+		out = append(out, resp)
+	}
+	tt.err(assert)(sub.Err())
+	assert.NoError(sub.Close())
+	assert.Equal(tt.out, out)
+	assert.Equal(tt.startType, tt.server.start.Type)
+	assert.Equal(subscriptionID, tt.server.start.ID)
+	var payload Raw
+	assert.NoError(json.Unmarshal(tt.server.start.Payload, &payload))
+	assert.Equal("subscription { field }", payload.Query)
+	assert.Equal(tt.initPayload, tt.server.initPayload)
+	assert.Equal(tt.server.ping, tt.server.pong)
+}
+
+func TestClientSubscribe(t *testing.T) {
+	event := map[string]interface{}{
+		"data": map[string]interface{}{"field": "value"},
+	}
+	data := []testCaseSubscribe{
+		{
+			server: &wsTestServer{
+				protocols: []string{ProtocolGraphQLTransportWS},
+				events:    []interface{}{event, event},
+			},
+			startType: "subscribe",
+			out: []Response{
+				Response{Data: map[string]interface{}{"field": "value"}},
+				Response{Data: map[string]interface{}{"field": "value"}},
+			},
+		},
+		{
+			server: &wsTestServer{
+				protocols: []string{ProtocolGraphQLWS},
+				events:    []interface{}{event},
+			},
+			startType: "start",
+			out: []Response{
+				Response{Data: map[string]interface{}{"field": "value"}},
+			},
+		},
+		{
+			server: &wsTestServer{
+				protocols: []string{ProtocolGraphQLTransportWS},
+				ping:      true,
+			},
+			initPayload: map[string]interface{}{"token": "secret"},
+			startType:   "subscribe",
+		},
+		{
+			server: &wsTestServer{
+				protocols:  []string{ProtocolGraphQLTransportWS},
+				errPayload: []interface{}{map[string]interface{}{"message": "bad"}},
+			},
+			startType: "subscribe",
+			out: []Response{
+				Response{Errors: Errors{Error{Message: "bad"}}},
+			},
+		},
+		{
+			server: &wsTestServer{
+				protocols:  []string{ProtocolGraphQLWS},
+				errPayload: map[string]interface{}{"message": "bad"},
+			},
+			startType: "start",
+			out: []Response{
+				Response{Errors: Errors{Error{Message: "bad"}}},
+			},
+		},
+	}
+	for _, tt := range data {
+		tt.test(t)
+	}
+}
+
+func TestWSEndpoint(t *testing.T) {
+	assert := assert.New(t)
+	for in, out := range map[string]string{
+		"http://example.com/graphql":  "ws://example.com/graphql",
+		"https://example.com/graphql": "wss://example.com/graphql",
+		"wss://example.com/graphql":   "wss://example.com/graphql",
+	} {
+		endpoint, err := wsEndpoint(in)
+		assert.NoError(err)
+		assert.Equal(out, endpoint)
+	}
+	for _, in := range []string{"", "ftp://example.com", ":"} {
+		_, err := wsEndpoint(in)
+		assert.Error(err)
+	}
+}
+
+func TestSubscriptionClose(t *testing.T) {
+	assert := assert.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{Subprotocols: []string{ProtocolGraphQLTransportWS}}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var msg wsMessage
+		conn.ReadJSON(&msg)
+		conn.WriteJSON(wsMessage{Type: "connection_ack"})
+		conn.ReadJSON(&msg)
+		for {
+			if err := conn.WriteJSON(wsMessage{
+				ID:      msg.ID,
+				Type:    "next",
+				Payload: json.RawMessage(`{"data":{"tick":1}}`),
+			}); err != nil {
+				return
+			}
+			var stop wsMessage
+			if err := conn.ReadJSON(&stop); err != nil || stop.Type == "complete" {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+	sub, err := New(Config{Endpoint: srv.URL}).Subscribe(Raw{Query: "subscription { tick }"})
+	assert.NoError(err)
+	resp := <-sub.Events
+	assert.Equal(map[string]interface{}{"tick": float64(1)}, resp.Data)
+	assert.NoError(sub.Close())
+	_, ok := <-sub.Events
+	assert.False(ok)
+	assert.NoError(sub.Err())
+	_, err = New(Config{Endpoint: strings.Replace(srv.URL, "http", "ftp", 1)}).Subscribe(Raw{Query: "subscription { tick }"})
+	assert.Error(err)
+}
+
+func TestClientSubscribeContext(t *testing.T) {
+	assert := assert.New(t)
+	srv := httptest.NewServer(&wsTestServer{
+		protocols: []string{ProtocolGraphQLTransportWS},
+		hold:      true,
+	})
+	defer srv.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	sub, err := New(Config{Endpoint: srv.URL}).SubscribeContext(ctx, Raw{Query: "subscription { field }"})
+	assert.NoError(err)
+	cancel()
+	for range sub.Events {
+	}
+	assert.Equal(context.Canceled, sub.Err())
+}
+
+func TestClientSubscribeNoAck(t *testing.T) {
+	assert := assert.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{Subprotocols: []string{ProtocolGraphQLTransportWS}}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// never acknowledge connection
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+	cli := New(Config{Endpoint: srv.URL})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := cli.SubscribeContext(ctx, Raw{Query: "subscription { field }"})
+	assert.Error(err)
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	_, err = cli.SubscribeContext(ctx, Raw{Query: "subscription { field }"})
+	assert.Equal(context.Canceled, err)
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Upload is a file sent along with the query using GraphQL
+// multipart request specification. Upload can be used as
+// a value of any variable, including values nested in lists
+// and input objects.
+type Upload struct {
+	// Filename is a name of the file sent to server
+	Filename string
+	// ContentType is an optional media type of the file,
+	// defaults to application/octet-stream
+	ContentType string
+	// Open returns file contents, it is called each time
+	// request is sent and contents are streamed to server
+	Open func() (io.ReadCloser, error)
+}
+
+// FileUpload returns Upload of a file at path
+func FileUpload(path string) *Upload {
+	return &Upload{
+		Filename: filepath.Base(path),
+		Open: func() (io.ReadCloser, error) {
+			return os.Open(path)
+		},
+	}
+}
+
+// MarshalJSON encodes Upload as null, as specification
+// requires files to be replaced with null in operations
+func (u *Upload) MarshalJSON() ([]byte, error) {
+	return []byte("null"), nil
+}
+
+// uploadPath is a path to an Upload in request object
+type uploadPath struct {
+	path   string
+	upload *Upload
+}
+
+// findUploads returns paths of all uploads in v
+func findUploads(prefix string, v interface{}) []uploadPath {
+	var uploads []uploadPath
+	switch vt := v.(type) {
+	case *Upload:
+		if vt != nil {
+			uploads = append(uploads, uploadPath{path: prefix, upload: vt})
+		}
+	case map[string]interface{}:
+		for k, v := range vt {
+			uploads = append(uploads, findUploads(prefix+"."+k, v)...)
+		}
+	case []interface{}:
+		for i, v := range vt {
+			uploads = append(uploads, findUploads(prefix+"."+strconv.Itoa(i), v)...)
+		}
+	}
+	return uploads
+}
+
+// uploads returns all files attached to request variables,
+// paths are prefixed with prefix
+func (r Raw) uploads(prefix string) []uploadPath {
+	var uploads []uploadPath
+	for k, v := range r.Variables {
+		uploads = append(uploads, findUploads(prefix+"variables."+k, v)...)
+	}
+	sort.Slice(uploads, func(i, j int) bool {
+		return uploads[i].path < uploads[j].path
+	})
+	return uploads
+}
+
+// multipartBody streams operations as GraphQL multipart
+// request, returning body and its content type
+func multipartBody(ops interface{}, uploads []uploadPath) (io.ReadCloser, string, error) {
+	operations, err := json.Marshal(ops)
+	if err != nil {
+		return nil, "", err
+	}
+	fileMap := make(map[string][]string, len(uploads))
+	for i, u := range uploads {
+		fileMap[strconv.Itoa(i)] = []string{u.path}
+	}
+	b, err := json.Marshal(fileMap)
+	if err != nil {
+		return nil, "", err
+	}
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeMultipart(mw, operations, b, uploads)) // nolint: errcheck
+	}()
+	return pr, mw.FormDataContentType(), nil
+}
+
+func writeMultipart(mw *multipart.Writer, operations, fileMap []byte, uploads []uploadPath) error {
+	if err := mw.WriteField("operations", string(operations)); err != nil {
+		return err
+	}
+	if err := mw.WriteField("map", string(fileMap)); err != nil {
+		return err
+	}
+	for i, u := range uploads {
+		if err := writeUpload(mw, strconv.Itoa(i), u.upload); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func writeUpload(mw *multipart.Writer, name string, u *Upload) error {
+	if u.Open == nil {
+		return fmt.Errorf("upload %s cannot be opened", u.Filename)
+	}
+	f, err := u.Open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	contentType := u.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(
+		`form-data; name="%s"; filename="%s"`,
+		name,
+		quoteEscaper.Replace(u.Filename),
+	))
+	h.Set("Content-Type", contentType)
+	w, err := mw.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func stringUpload(name, contents string) *Upload {
+	return &Upload{
+		Filename: name,
+		Open: func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(strings.NewReader(contents)), nil
+		},
+	}
+}
+
+func TestRawUploads(t *testing.T) {
+	assert := assert.New(t)
+	a, b, c := stringUpload("a", ""), stringUpload("b", ""), stringUpload("c", "")
+	r := Raw{
+		Variables: map[string]interface{}{
+			"file":  a,
+			"files": []interface{}{b, c},
+			"input": map[string]interface{}{
+				"name": "some-name",
+			},
+		},
+	}
+	assert.Equal([]uploadPath{
+		{path: "variables.file", upload: a},
+		{path: "variables.files.0", upload: b},
+		{path: "variables.files.1", upload: c},
+	}, r.uploads(""))
+	assert.Nil(Raw{Variables: map[string]interface{}{"a": "b"}}.uploads(""))
+}
+
+func TestClientUpload(t *testing.T) {
+	assert := assert.New(t)
+	type part struct {
+		filename    string
+		contentType string
+		body        string
+	}
+	var operations, fileMap string
+	parts := make(map[string]part)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mr, err := r.MultipartReader()
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for {
+			p, err := mr.NextPart()
+			if err != nil {
+				break
+			}
+			b, _ := ioutil.ReadAll(p)
+			switch p.FormName() {
+			case "operations":
+				operations = string(b)
+			case "map":
+				fileMap = string(b)
+			default:
+				parts[p.FormName()] = part{
+					filename:    p.FileName(),
+					contentType: p.Header.Get("Content-Type"),
+					body:        string(b),
+				}
+			}
+		}
+		fmt.Fprint(w, `{"data":{"upload":true}}`)
+	}))
+	defer srv.Close()
+	file := stringUpload("a.txt", "contents of a")
+	file.ContentType = "text/plain"
+	data, err := New(Config{Endpoint: srv.URL}).Raw(Raw{
+		Query: "mutation($file: Upload!, $files: [Upload!]) { upload(file: $file, files: $files) }",
+		Variables: map[string]interface{}{
+			"file":  file,
+			"files": []interface{}{stringUpload("b.bin", "contents of b")},
+		},
+	}, nil)
+	assert.NoError(err)
+	assert.Equal(map[string]interface{}{"upload": true}, data)
+	var ops map[string]interface{}
+	assert.NoError(json.Unmarshal([]byte(operations), &ops))
+	assert.Equal(map[string]interface{}{
+		"file":  nil,
+		"files": []interface{}{nil},
+	}, ops["variables"])
+	assert.JSONEq(`{"0":["variables.file"],"1":["variables.files.0"]}`, fileMap)
+	assert.Equal(map[string]part{
+		"0": {filename: "a.txt", contentType: "text/plain", body: "contents of a"},
+		"1": {filename: "b.bin", contentType: "application/octet-stream", body: "contents of b"},
+	}, parts)
+}
+
+func TestClientUploadErrors(t *testing.T) {
+	assert := assert.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body) // nolint: errcheck
+		fmt.Fprint(w, `{"data":{"upload":true}}`)
+	}))
+	defer srv.Close()
+	cli := New(Config{Endpoint: srv.URL})
+	_, err := cli.Raw(Raw{
+		Query: "mutation($file: Upload!) { upload(file: $file) }",
+		Variables: map[string]interface{}{
+			"file": &Upload{
+				Filename: "a",
+				Open: func() (io.ReadCloser, error) {
+					return nil, errors.New("cannot open")
+				},
+			},
+		},
+	}, nil)
+	assert.Error(err)
+	_, err = cli.Raw(Raw{
+		Query:     "mutation($file: Upload!) { upload(file: $file) }",
+		Variables: map[string]interface{}{"file": stringUpload("a", "a")},
+		Method:    http.MethodGet,
+	}, nil)
+	assert.Error(err)
+}
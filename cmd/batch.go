@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/slothking-online/gql/client"
+
+	"github.com/spf13/cobra"
+)
+
+type BatchCommandConfig struct {
+	Config
+}
+
+// readBatch reads newline delimited JSON queries, skipping blank lines
+func readBatch(in io.Reader, header http.Header) ([]client.Raw, error) {
+	var rs []client.Raw
+	rd := bufio.NewReader(in)
+	for line := 1; ; line++ {
+		b, err := rd.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if b = bytes.TrimSpace(b); len(b) != 0 {
+			var r client.Raw
+			if jerr := json.Unmarshal(b, &r); jerr != nil {
+				return nil, fmt.Errorf("line %d: %s", line, jerr.Error())
+			}
+			if r.Query == "" {
+				return nil, fmt.Errorf("line %d: query cannot be empty", line)
+			}
+			r.Header = header
+			rs = append(rs, r)
+		}
+		if err == io.EOF {
+			return rs, nil
+		}
+	}
+}
+
+// writeBatch writes each response as a single line of JSON
+func writeBatch(out io.Writer, responses []client.Response) error {
+	for _, resp := range responses {
+		b, err := json.Marshal(resp)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(out, string(b)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewBatchCommand creates batch command
+func NewBatchCommand(config BatchCommandConfig) *cobra.Command {
+	var Endpoint string
+	var batchSize int
+	header := make(Header)
+	batchCmd := &cobra.Command{
+		Use:   "batch [file]",
+		Short: "Execute a batch of queries in a single request",
+		Long: `Executes a batch of GraphQL queries against http GraphQL backend supporting query batching.
+
+Queries are read from file, or from standard input if file is not given or is "-", as newline delimited JSON objects with query, variables and operationName fields. Results are written as newline delimited JSON in the same order as queries.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			in := config.Input()
+			if len(args) == 1 && args[0] != "-" {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				in = f
+			}
+			if batchSize < 0 {
+				return errors.New("batch-size cannot be negative")
+			}
+			httpHeader := make(http.Header)
+			for k, v := range header {
+				httpHeader.Add(k, v)
+			}
+			rs, err := readBatch(in, httpHeader)
+			if err != nil {
+				return err
+			}
+			cli := newClient(Endpoint, connectTimeout)
+			ctx, cancel := requestContext()
+			defer cancel()
+			for len(rs) != 0 {
+				n := len(rs)
+				if batchSize != 0 && batchSize < n {
+					n = batchSize
+				}
+				responses, err := cli.BatchContext(ctx, rs[:n])
+				if err != nil {
+					return err
+				}
+				if err := writeBatch(config.Output(), responses); err != nil {
+					return err
+				}
+				rs = rs[n:]
+			}
+			return nil
+		},
+	}
+	requiredEndpointFlag(&Endpoint, batchCmd.Flags())
+	headersFlag(header, batchCmd.Flags())
+	timeoutFlags(batchCmd.Flags())
+	retryFlags(batchCmd.Flags())
+	batchCmd.Flags().IntVar(
+		&batchSize,
+		"batch-size",
+		0,
+		"maximum number of queries sent in a single request, all queries are sent at once if 0",
+	)
+	return batchCmd
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aexol/test_util"
+	"github.com/slothking-online/gql/client"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCaseReadBatch struct {
+	in  string
+	out []client.Raw
+	err func(*assert.Assertions) test_util.ErrorAssertion
+}
+
+func (tt testCaseReadBatch) test(t *testing.T) {
+	assert := assert.New(t)
+	if tt.err == nil {
+		tt.err = test_util.NoError
+	}
+	out, err := readBatch(strings.NewReader(tt.in), nil)
+	tt.err(assert)(err)
+	assert.Equal(tt.out, out)
+}
+
+func TestReadBatch(t *testing.T) {
+	data := []testCaseReadBatch{
+		{
+			in: "{\"query\":\"{ a }\"}\n\n{\"query\":\"{ b }\",\"variables\":{\"v\":1}}",
+			out: []client.Raw{
+				{Query: "{ a }"},
+				{Query: "{ b }", Variables: map[string]interface{}{"v": float64(1)}},
+			},
+		},
+		{
+			in: "",
+		},
+		{
+			in:  "{\"query\":\"{ a }\"}\nnot json\n",
+			err: test_util.Error,
+		},
+		{
+			in:  "{\"variables\":{}}\n",
+			err: test_util.Error,
+		},
+	}
+	for _, tt := range data {
+		tt.test(t)
+	}
+}
+
+func TestBatchCommand(t *testing.T) {
+	assert := assert.New(t)
+	var sizes []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var rs []client.Raw
+		b, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(b, &rs) // nolint: errcheck
+		sizes = append(sizes, len(rs))
+		responses := make([]client.Response, 0, len(rs))
+		for _, r := range rs {
+			responses = append(responses, client.Response{
+				Data: map[string]interface{}{"query": r.Query},
+			})
+		}
+		json.NewEncoder(w).Encode(responses) // nolint: errcheck
+	}))
+	defer srv.Close()
+	out := &bytes.Buffer{}
+	cmd := NewBatchCommand(BatchCommandConfig{
+		Config: Config{
+			In:  strings.NewReader("{\"query\":\"{ a }\"}\n{\"query\":\"{ b }\"}\n{\"query\":\"{ c }\"}\n"),
+			Out: out,
+		},
+	})
+	cmd.SetArgs([]string{"--endpoint", srv.URL, "--batch-size", "2"})
+	assert.NoError(cmd.Execute())
+	assert.Equal([]int{2, 1}, sizes)
+	expected := ""
+	for _, q := range []string{"a", "b", "c"} {
+		expected += fmt.Sprintf("{\"data\":{\"query\":\"{ %s }\"}}\n", q)
+	}
+	assert.Equal(expected, out.String())
+}
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/pflag"
 
@@ -49,6 +50,8 @@ func (c completionType) String() string {
 		return "cmd"
 	case opt:
 		return "opt"
+	case val:
+		return "val"
 	}
 	return ""
 }
@@ -69,6 +72,11 @@ const (
 _gql_completions() {
     cur="${COMP_WORDS[COMP_CWORD]}"
     completion="$(gql completion "${COMP_LINE}")"
+    val="$(echo "${completion}" | grep '^val' | cut -d ':' -f 2 | tr "\n" " ")"
+    if [ -n "${val}" ]; then
+        COMPREPLY=($(compgen -W "${val}" -- "${cur}"))
+        return
+    fi
     cmd="$(echo "${completion}" | grep '^cmd' | cut -d ':' -f 2 | tr "\n" " ")"
     opt="$(echo "${completion}" | grep '^opt' | cut -d ':' -f 2 | tr "\n" " ")"
     COMPREPLY=($(compgen -W "${cmd} ${opt}" -- "${cur}"))
@@ -115,6 +123,11 @@ _gql_completions() {
 	if [ "$?" != "0" ]; then
 		return
 	fi
+	vals="$(echo "${completion}" | grep "^val:")"
+	if [ -n "${vals}" ]; then
+		_alternative 'vals:argument values:(('"$(clean "${vals}")"'))'
+		return
+	fi
 	_alternative \
 		'args=:arguments with additional value:(('"$(_gql_args_val "${completion}")"'))' \
 		'args=:available fields:(('"$(_gql_fields "${completion}")"'))' \
@@ -124,6 +137,7 @@ _gql_completions() {
 compdef _gql_completions gql -p "gql *"`
 	cmd completionType = iota
 	opt
+	val
 )
 
 type completion struct {
@@ -157,6 +171,9 @@ func NewCompletionCommand(config CompletionCommandConfig) *cobra.Command {
 				_, err = fmt.Fprintln(config.Output(), zshCompletion)
 				return
 			}
+			// trailing space means that completed
+			// word is a new one
+			newWord := strings.HasSuffix(args[0], " ")
 			args, err = shellquote.Split(args[0])
 			// Strip leading command name
 			if err != nil {
@@ -167,6 +184,11 @@ func NewCompletionCommand(config CompletionCommandConfig) *cobra.Command {
 			if commandBuilder == nil {
 				commandBuilder = CommandBuilderFunc(NewRootCommand)
 			}
+			// Keep completion responsive on slow endpoints
+			introspectionTimeout = CompletionTimeout
+			defer func() {
+				introspectionTimeout = 0
+			}()
 			cmd = commandBuilder.New(args)
 
 			// Parse all the complation args
@@ -177,8 +199,13 @@ func NewCompletionCommand(config CompletionCommandConfig) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			completions := getFlagCompletions(cmd)
-			completions = append(completions, getSubcommandCompletions(cmd)...)
+			// complete only flag value if it
+			// has a known set of values
+			completions := getValueCompletions(cmd, args, newWord)
+			if len(completions) == 0 {
+				completions = getFlagCompletions(cmd)
+				completions = append(completions, getSubcommandCompletions(cmd)...)
+			}
 			buf := &bytes.Buffer{}
 			for _, c := range completions {
 				if _, err = fmt.Fprintf(buf, "%s:%s:%s:%t\n", c.cType.String(), c.name, c.description, c.hasArg); err != nil {
@@ -222,3 +249,31 @@ func getFlagCompletions(c *cobra.Command) []completion {
 	c.InheritedFlags().VisitAll(addCompletion)
 	return completions
 }
+
+// valueCompleter is implemented by flag values
+// with a known set of possible values
+type valueCompleter interface {
+	completions() []completion
+}
+
+// getValueCompletions returns possible values of a flag being
+// completed, if newWord is false last argument is a partial value
+func getValueCompletions(c *cobra.Command, args []string, newWord bool) []completion {
+	if !newWord {
+		if len(args) == 0 || strings.HasPrefix(args[len(args)-1], "-") {
+			return nil
+		}
+		args = args[:len(args)-1]
+	}
+	if len(args) == 0 || !strings.HasPrefix(args[len(args)-1], "--") {
+		return nil
+	}
+	flag := c.Flags().Lookup(strings.TrimPrefix(args[len(args)-1], "--"))
+	if flag == nil {
+		return nil
+	}
+	if vc, ok := flag.Value.(valueCompleter); ok {
+		return vc.completions()
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"github.com/aexol/test_util"
+	"github.com/slothking-online/gql/introspection"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
@@ -234,3 +235,41 @@ func TestFlagCompletions(t *testing.T) {
 		},
 	}, getFlagCompletions(sc))
 }
+
+func TestEnumValueCompletions(t *testing.T) {
+	assert := assert.New(t)
+	schema, err := introspection.ParseSDL(`
+enum Kind {
+  NEWS
+  "Old posts"
+  BLOG @deprecated(reason: "Use NEWS")
+}
+input Filter { kind: Kind }
+type Query { posts(kind: Kind, kinds: [Kind!], filter: Filter, text: String): String }
+`)
+	if !assert.NoError(err) {
+		return
+	}
+	field, ok := schema.FieldForPath([]string{"query", "posts"})
+	if !assert.True(ok) {
+		return
+	}
+	fc := NewFieldCommand(field, schema, nil, nil)
+	values := []completion{
+		{cType: val, name: "NEWS"},
+		{cType: val, name: "BLOG", description: "(deprecated: Use NEWS) Old posts"},
+	}
+	assert.Equal(values, getValueCompletions(fc.Command, []string{"--arg-kind"}, true))
+	assert.Equal(values, getValueCompletions(fc.Command, []string{"--arg-kind", "NE"}, false))
+	assert.Equal(values, getValueCompletions(fc.Command, []string{"--arg-kinds"}, true))
+	assert.Equal(values, getValueCompletions(fc.Command, []string{"--arg-filter.kind"}, true))
+	assert.Empty(getValueCompletions(fc.Command, []string{"--arg-kind", "NEWS"}, true))
+	assert.Empty(getValueCompletions(fc.Command, []string{"--arg-kind", "--arg"}, false))
+	assert.Empty(getValueCompletions(fc.Command, []string{"--arg-text"}, true))
+	assert.Empty(getValueCompletions(fc.Command, []string{"--missing"}, true))
+	assert.EqualError(fc.Command.Flags().Lookup("arg-kind").Value.Set("NEW"), "invalid Kind value NEW, did you mean NEWS?")
+	assert.EqualError(fc.Command.Flags().Lookup("arg-kind").Value.Set("POST"), "invalid Kind value POST, expected one of NEWS, BLOG")
+	assert.EqualError(fc.Command.Flags().Lookup("arg-kinds").Value.Set("[NEWS, BLOF]"), "invalid Kind value BLOF, did you mean BLOG?")
+	assert.EqualError(fc.Command.Flags().Lookup("arg-filter.kind").Value.Set("NEW"), "invalid Kind value NEW, did you mean NEWS?")
+	assert.NoError(fc.Command.Flags().Lookup("arg-kind").Value.Set("BLOG"))
+}
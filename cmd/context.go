@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/slothking-online/gql/client"
+	"github.com/spf13/pflag"
+)
+
+var (
+	// CompletionTimeout limits schema introspection done
+	// while completing command line, so that completion
+	// never hangs the shell on unresponsive endpoint
+	CompletionTimeout = 3 * time.Second
+
+	timeout        time.Duration
+	connectTimeout time.Duration
+	retry          int
+	retryMaxTime   time.Duration
+	retryMutations bool
+	persisted      bool
+	// overrides --timeout for schema introspection, if set
+	introspectionTimeout time.Duration
+	// baseContext is cancelled on interrupt
+	baseContext = context.Background()
+)
+
+func timeoutFlags(flags *pflag.FlagSet) {
+	flags.DurationVar(
+		&timeout,
+		"timeout",
+		0,
+		"maximum time of the whole operation, no limit if 0",
+	)
+	flags.DurationVar(
+		&connectTimeout,
+		"connect-timeout",
+		0,
+		"maximum time spent on connecting to the endpoint, no limit if 0",
+	)
+}
+
+func retryFlags(flags *pflag.FlagSet) {
+	flags.IntVar(
+		&retry,
+		"retry",
+		0,
+		"retry failed query up to N times on transient errors",
+	)
+	flags.DurationVar(
+		&retryMaxTime,
+		"retry-max-time",
+		0,
+		"stop retrying once this much time has passed, no limit if 0",
+	)
+	flags.BoolVar(
+		&retryMutations,
+		"retry-mutations",
+		false,
+		"allow retrying mutations, which may not be idempotent",
+	)
+}
+
+// withTimeout returns context derived from base context
+// limited by d, unless d is zero
+func withTimeout(d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(baseContext)
+	}
+	return context.WithTimeout(baseContext, d)
+}
+
+// requestContext returns context for a request
+// limited by --timeout flag
+func requestContext() (context.Context, context.CancelFunc) {
+	return withTimeout(timeout)
+}
+
+func newClient(endpoint string, connectTimeout time.Duration) *client.Client {
+	var policy *client.RetryPolicy
+	if retry > 0 {
+		policy = &client.RetryPolicy{
+			MaxAttempts:    retry + 1,
+			MaxElapsed:     retryMaxTime,
+			RetryMutations: retryMutations,
+		}
+	}
+	cfg := client.Config{
+		Endpoint:       endpoint,
+		ConnectTimeout: connectTimeout,
+		Retry:          policy,
+		Persisted:      persisted,
+	}
+	if persisted {
+		cfg.PersistedCache = newPersistedCache(endpoint)
+	}
+	return client.New(cfg)
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"text/template"
@@ -31,13 +32,68 @@ func formattedOutput(config Config, b []byte) (bool, error) {
 	return err == nil, err
 }
 
-func execute(config Config, cli *client.Client, r client.Raw, out interface{}) error {
-	data, qerr := cli.Raw(r, out)
+func execute(ctx context.Context, config Config, cli *client.Client, r client.Raw, out interface{}) error {
+	switch incremental {
+	case incrementalMerge, "":
+	case incrementalStream:
+		return executeStream(ctx, config, cli, r, out)
+	default:
+		return fmt.Errorf("unknown incremental mode %s", incremental)
+	}
+	data, qerr := cli.RawContext(ctx, r, out)
 	if qerr != nil {
 		if _, ok := qerr.(client.Errors); !ok {
 			return qerr
 		}
 	}
+	return printResponse(config, data, qerr)
+}
+
+// executeStream prints each payload of incremental
+// response as it arrives
+func executeStream(ctx context.Context, config Config, cli *client.Client, r client.Raw, out interface{}) error {
+	_, qerr := cli.RawIncremental(ctx, r, out, func(p client.Patch) error {
+		return printResponse(config, p, nil)
+	})
+	if _, ok := qerr.(client.Errors); ok {
+		// already printed with patches
+		return nil
+	}
+	return qerr
+}
+
+// subscribe prints each result pushed by remote endpoint
+// until the subscription completes
+func subscribe(ctx context.Context, config Config, cli *client.Client, r client.Raw) error {
+	var sub *client.Subscription
+	var err error
+	switch transport {
+	case transportWS, "":
+		sub, err = cli.SubscribeContext(ctx, r)
+	case transportSSE:
+		sub, err = cli.SubscribeSSEContext(ctx, r)
+	case transportHTTP:
+		return execute(ctx, config, cli, r, nil)
+	default:
+		err = fmt.Errorf("unknown transport %s", transport)
+	}
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+	for resp := range sub.Events {
+		var qerr error
+		if len(resp.Errors) != 0 {
+			qerr = resp.Errors
+		}
+		if err := printResponse(config, resp.Data, qerr); err != nil {
+			return err
+		}
+	}
+	return sub.Err()
+}
+
+func printResponse(config Config, data interface{}, qerr error) error {
 	if data != nil {
 		b, err := json.MarshalIndent(data, "", "    ")
 		if err != nil {
@@ -1,8 +1,16 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/slothking-online/gql/client"
+
 	"github.com/aexol/test_util"
 
 	"github.com/stretchr/testify/assert"
@@ -79,3 +87,41 @@ func TestFormattedOutput(t *testing.T) {
 }
 
 func TestExecute(t *testing.T) {}
+
+func TestExecuteIncremental(t *testing.T) {
+	assert := assert.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", `multipart/mixed; boundary="-"`)
+		fmt.Fprint(w, "---\r\nContent-Type: application/json\r\n\r\n")
+		fmt.Fprint(w, `{"data":{"a":1},"hasNext":true}`)
+		fmt.Fprint(w, "\r\n---\r\nContent-Type: application/json\r\n\r\n")
+		fmt.Fprint(w, `{"incremental":[{"data":{"b":2},"path":[]}],"hasNext":false}`)
+		fmt.Fprint(w, "\r\n-----\r\n")
+	}))
+	defer srv.Close()
+	defer func() {
+		incremental = incrementalMerge
+	}()
+	cli := client.New(client.Config{Endpoint: srv.URL})
+	r := client.Raw{Query: "{ a ... @defer { b } }"}
+	out := &bytes.Buffer{}
+	format = ""
+	incremental = incrementalMerge
+	assert.NoError(execute(context.Background(), Config{Out: out}, cli, r, nil))
+	assert.JSONEq(`{"a":1,"b":2}`, out.String())
+	out.Reset()
+	incremental = incrementalStream
+	assert.NoError(execute(context.Background(), Config{Out: out}, cli, r, nil))
+	dec := json.NewDecoder(out)
+	var patches []map[string]interface{}
+	for dec.More() {
+		var p map[string]interface{}
+		assert.NoError(dec.Decode(&p))
+		patches = append(patches, p)
+	}
+	assert.Len(patches, 2)
+	assert.Equal(true, patches[0]["hasNext"])
+	assert.Equal(false, patches[1]["hasNext"])
+	incremental = "other"
+	assert.Error(execute(context.Background(), Config{Out: out}, cli, r, nil))
+}
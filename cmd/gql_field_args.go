@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 
+	"github.com/agnivade/levenshtein"
+
+	"github.com/slothking-online/gql/client"
 	"github.com/slothking-online/gql/introspection"
 
 	"github.com/spf13/pflag"
@@ -10,6 +15,10 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// uploadScalar is a name of scalar used for file uploads
+// by GraphQL multipart request specification
+const uploadScalar = "Upload"
+
 type FieldCommandValue interface {
 	// GraphQL-like string of value
 	String() string
@@ -47,6 +56,10 @@ func (f *FieldCommandIntArgument) Name() string {
 	return f.name
 }
 
+func (f *FieldCommandIntArgument) Variable() interface{} {
+	return f.value
+}
+
 type FieldCommandFloatArgument struct {
 	name  string
 	value float64
@@ -68,13 +81,17 @@ func (f *FieldCommandFloatArgument) Name() string {
 	return f.name
 }
 
+func (f *FieldCommandFloatArgument) Variable() interface{} {
+	return f.value
+}
+
 type FieldCommandStringArgument struct {
 	name  string
 	value string
 }
 
 func (f *FieldCommandStringArgument) String() string {
-	return fmt.Sprintf("\"%s\"", f.value)
+	return quote(f.value)
 }
 
 func (f *FieldCommandStringArgument) Type() string {
@@ -89,6 +106,10 @@ func (f *FieldCommandStringArgument) Name() string {
 	return f.name
 }
 
+func (f *FieldCommandStringArgument) Variable() interface{} {
+	return f.value
+}
+
 type FieldCommandBooleanArgument struct {
 	name  string
 	value bool
@@ -110,13 +131,17 @@ func (f *FieldCommandBooleanArgument) Name() string {
 	return f.name
 }
 
+func (f *FieldCommandBooleanArgument) Variable() interface{} {
+	return f.value
+}
+
 type FieldCommandIDArgument struct {
 	name  string
 	value string
 }
 
 func (f *FieldCommandIDArgument) String() string {
-	return fmt.Sprintf("\"%s\"", f.value)
+	return quote(f.value)
 }
 
 func (f *FieldCommandIDArgument) Type() string {
@@ -131,14 +156,18 @@ func (f *FieldCommandIDArgument) Name() string {
 	return f.name
 }
 
+func (f *FieldCommandIDArgument) Variable() interface{} {
+	return f.value
+}
+
 type FieldCommandEnumArgument struct {
 	name     string
-	value    string
+	value    enumValue
 	enumName string
 }
 
 func (f *FieldCommandEnumArgument) String() string {
-	return f.value
+	return f.value.value
 }
 
 func (f *FieldCommandEnumArgument) Type() string {
@@ -153,6 +182,80 @@ func (f *FieldCommandEnumArgument) Name() string {
 	return f.name
 }
 
+func (f *FieldCommandEnumArgument) Variable() interface{} {
+	return f.value.value
+}
+
+// enumValue is a value of enum argument flag,
+// checked against values of the enum
+type enumValue struct {
+	value    string
+	enumName string
+	// values of enum, if empty
+	// any value is accepted
+	values []introspection.EnumValue
+}
+
+func (e *enumValue) String() string {
+	return e.value
+}
+
+func (e *enumValue) Set(s string) error {
+	if err := e.check(s); err != nil {
+		return err
+	}
+	e.value = s
+	return nil
+}
+
+func (e *enumValue) Type() string {
+	return e.enumName
+}
+
+// check returns an error if s is not a value of enum,
+// suggesting values that are close to s
+func (e *enumValue) check(s string) error {
+	if len(e.values) == 0 {
+		return nil
+	}
+	var matches, names []string
+	for _, v := range e.values {
+		if v.Name == s {
+			return nil
+		}
+		names = append(names, v.Name)
+		// Only accept matches with distance of less
+		// than 3 edits
+		if levenshtein.ComputeDistance(s, v.Name) < 3 {
+			matches = append(matches, v.Name)
+		}
+	}
+	if len(matches) != 0 {
+		return fmt.Errorf("invalid %s value %s, did you mean %s?", e.enumName, s, strings.Join(matches, " or "))
+	}
+	return fmt.Errorf("invalid %s value %s, expected one of %s", e.enumName, s, strings.Join(names, ", "))
+}
+
+func (e *enumValue) completions() []completion {
+	compl := make([]completion, 0, len(e.values))
+	for _, v := range e.values {
+		description := v.Description
+		if v.IsDeprecated {
+			reason := v.DeprecationReason
+			if reason == "" {
+				reason = introspection.DefaultDeprecationReason
+			}
+			description = strings.TrimSpace("(deprecated: " + reason + ") " + description)
+		}
+		compl = append(compl, completion{
+			cType:       val,
+			name:        v.Name,
+			description: description,
+		})
+	}
+	return compl
+}
+
 type FieldCommandNonNullArgument struct {
 	FieldCommandArgument
 }
@@ -167,7 +270,11 @@ type nonNullSetter struct {
 
 func (n *nonNullSetter) Set(cmd *cobra.Command, f FieldCommandArgument) {
 	Set(cmd, (*fieldCommandNonNullArgument)(n.f))
-	cmd.MarkFlagRequired(FieldCommandArgName(n.f))
+	// input objects can be set with field flags alone,
+	// so they are checked when arguments are validated
+	if _, ok := n.f.FieldCommandArgument.(*FieldCommandInputArgument); !ok {
+		cmd.MarkFlagRequired(FieldCommandArgName(n.f))
+	}
 }
 
 func (f *FieldCommandNonNullArgument) Value() interface{} {
@@ -184,68 +291,117 @@ type Setter interface {
 	Set(*cobra.Command, FieldCommandArgument)
 }
 
-type FieldCommandInputArgument struct {
-	name string
-	// should there be some client side parsing to
-	// check for valid input?
-	value     string
-	inputName string
+// literalValue is a flag value holding GraphQL value literal,
+// literal is parsed when flag is set
+type literalValue struct {
+	literal string
+	value   interface{}
+	// lenient literals that are not valid GraphQL
+	// are kept as a plain string
+	lenient bool
 }
 
-func (f *FieldCommandInputArgument) String() string {
-	return f.value
+func (l *literalValue) String() string {
+	return l.literal
+}
+
+func (l *literalValue) Set(s string) error {
+	v, err := introspection.ParseValue(s)
+	if err != nil {
+		if !l.lenient {
+			return err
+		}
+		v = s
+	}
+	l.literal = s
+	l.value = v
+	return nil
+}
+
+func (l *literalValue) Type() string {
+	return "value"
+}
+
+type FieldCommandCustomScalarArgument struct {
+	name       string
+	value      literalValue
+	scalarName string
+}
+
+func (f *FieldCommandCustomScalarArgument) String() string {
+	// Return custom scalar as is, without any changes
+	return f.value.literal
 }
 
-func (f *FieldCommandInputArgument) Value() interface{} {
+func (f *FieldCommandCustomScalarArgument) Value() interface{} {
 	return &f.value
 }
 
-func (f *FieldCommandInputArgument) Type() string {
-	return f.inputName
+func (f *FieldCommandCustomScalarArgument) Type() string {
+	return f.scalarName
 }
 
-func (f *FieldCommandInputArgument) Name() string {
+func (f *FieldCommandCustomScalarArgument) Name() string {
 	return f.name
 }
 
-type FieldCommandListArgument struct {
+func (f *FieldCommandCustomScalarArgument) Variable() interface{} {
+	return f.value.value
+}
+
+// FieldCommandVariable is an argument that is sent
+// as a query variable rather than inlined in query,
+// every argument type implements it
+type FieldCommandVariable interface {
 	FieldCommandArgument
+	// Variable returns value of query variable
+	Variable() interface{}
+}
+
+// represents GraphQL field argument of type Upload, value
+// of argument is a path to file, optionally prefixed with @
+type FieldCommandUploadArgument struct {
+	name  string
 	value string
 }
 
-func (f *FieldCommandListArgument) String() string {
+func (f *FieldCommandUploadArgument) String() string {
 	return f.value
 }
 
-func (f *FieldCommandListArgument) Value() interface{} {
+func (f *FieldCommandUploadArgument) Value() interface{} {
 	return &f.value
 }
 
-func (f *FieldCommandListArgument) Type() string {
-	return "[" + f.FieldCommandArgument.Type() + "]"
+func (f *FieldCommandUploadArgument) Type() string {
+	return uploadScalar
 }
 
-type FieldCommandCustomScalarArgument struct {
-	name       string
-	value      string
-	scalarName string
-}
-
-func (f *FieldCommandCustomScalarArgument) String() string {
-	// Return custom scalar as is, without any changes
-	return f.value
+func (f *FieldCommandUploadArgument) Name() string {
+	return f.name
 }
 
-func (f *FieldCommandCustomScalarArgument) Value() interface{} {
-	return &f.value
+func (f *FieldCommandUploadArgument) Variable() interface{} {
+	return client.FileUpload(strings.TrimPrefix(f.value, "@"))
 }
 
-func (f *FieldCommandCustomScalarArgument) Type() string {
-	return f.scalarName
+// unwrap non null argument and check if it
+// should be sent as variable
+func fieldCommandVariable(f FieldCommandArgument) (FieldCommandVariable, bool) {
+	switch ft := f.(type) {
+	case *FieldCommandNonNullArgument:
+		return fieldCommandVariable(ft.FieldCommandArgument)
+	case *fieldCommandNonNullArgument:
+		return fieldCommandVariable(ft.FieldCommandArgument)
+	}
+	v, ok := f.(FieldCommandVariable)
+	return v, ok
 }
 
-func (f *FieldCommandCustomScalarArgument) Name() string {
-	return f.name
+// quote returns GraphQL string literal of s
+func quote(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
 }
 
 func FieldCommandArgName(f FieldCommandArgument) string {
@@ -253,6 +409,9 @@ func FieldCommandArgName(f FieldCommandArgument) string {
 }
 
 func FieldCommandArgType(f FieldCommandArgument) string {
+	if listArgument(f) {
+		return fmt.Sprintf("Argument of type %s, repeat to add elements or pass an array", f.Type())
+	}
 	return fmt.Sprintf("Argument of type %s", f.Type())
 }
 
@@ -275,7 +434,7 @@ func Set(cmd *cobra.Command, f FieldCommandArgument) {
 	}
 }
 
-func getFieldCommandArgumentForArg(arg introspection.Arg) FieldCommandArgument {
+func getFieldCommandArgumentForArg(arg introspection.Arg, schema introspection.Schema) FieldCommandArgument {
 	switch {
 	case arg.Type.Scalar():
 		switch arg.Type.Name {
@@ -299,9 +458,14 @@ func getFieldCommandArgumentForArg(arg introspection.Arg) FieldCommandArgument {
 			return &FieldCommandIDArgument{
 				name: arg.Name,
 			}
+		case uploadScalar:
+			return &FieldCommandUploadArgument{
+				name: arg.Name,
+			}
 		default:
 			return &FieldCommandCustomScalarArgument{
 				name:       arg.Name,
+				value:      literalValue{lenient: true},
 				scalarName: arg.Type.Name,
 			}
 		}
@@ -309,32 +473,41 @@ func getFieldCommandArgumentForArg(arg introspection.Arg) FieldCommandArgument {
 		return &FieldCommandEnumArgument{
 			name:     arg.Name,
 			enumName: arg.Type.Name,
+			value: enumValue{
+				enumName: arg.Type.Name,
+				values:   arg.Type.Deref(schema.Types).EnumValues,
+			},
 		}
 	case arg.Type.Input():
 		return &FieldCommandInputArgument{
 			name:      arg.Name,
 			inputName: arg.Type.Name,
+			input:     arg.Type.Deref(schema.Types),
+			schema:    schema,
+			fields:    Variables{},
 		}
 	case arg.Type.NonNull():
 		nArg := arg
 		// TODO: better error message for panic here
 		nArg.Type = *nArg.Type.OfType
 		return &fieldCommandNonNullArgument{
-			FieldCommandArgument: getFieldCommandArgumentForArg(nArg),
+			FieldCommandArgument: getFieldCommandArgumentForArg(nArg, schema),
 		}
 	case arg.Type.List():
 		nArg := arg
 		// TODO: better error message for panic here
 		nArg.Type = *nArg.Type.OfType
+		of := getFieldCommandArgumentForArg(nArg, schema)
 		return &FieldCommandListArgument{
-			FieldCommandArgument: getFieldCommandArgumentForArg(nArg),
+			FieldCommandArgument: of,
+			value:                listValue{of: of},
 		}
 	default:
 		panic(fmt.Sprintf("malformed schema, %s cannot be used as input", arg.Type.Kind))
 	}
 }
 
-func GetFieldArguments(field introspection.Field) []FieldCommandArgument {
+func GetFieldArguments(field introspection.Field, schema introspection.Schema) []FieldCommandArgument {
 	args := make([]FieldCommandArgument, 0, len(field.Args))
 	for _, arg := range field.Args {
 		switch {
@@ -343,10 +516,10 @@ func GetFieldArguments(field introspection.Field) []FieldCommandArgument {
 			nArg := arg
 			nArg.Type = *arg.Type.OfType
 			args = append(args, &FieldCommandNonNullArgument{
-				FieldCommandArgument: getFieldCommandArgumentForArg(nArg),
+				FieldCommandArgument: getFieldCommandArgumentForArg(nArg, schema),
 			})
 		default:
-			args = append(args, getFieldCommandArgumentForArg(arg))
+			args = append(args, getFieldCommandArgumentForArg(arg, schema))
 		}
 	}
 	return args
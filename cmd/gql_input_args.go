@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/slothking-online/gql/introspection"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// represents GraphQL field argument of input object type, value
+// is either a GraphQL literal or a JSON/YAML file prefixed with @,
+// input fields can also be set with dotted flags, for example
+// --arg-input.title
+type FieldCommandInputArgument struct {
+	name      string
+	value     inputValue
+	inputName string
+	// input is a definition of input type
+	input  introspection.Type
+	schema introspection.Schema
+	// values of input field flags keyed by
+	// dotted path of a field
+	fields Variables
+}
+
+func (f *FieldCommandInputArgument) String() string {
+	return f.value.literal
+}
+
+func (f *FieldCommandInputArgument) Value() interface{} {
+	return &inputSetter{f: f}
+}
+
+func (f *FieldCommandInputArgument) Type() string {
+	return f.inputName
+}
+
+func (f *FieldCommandInputArgument) Name() string {
+	return f.name
+}
+
+// Variable returns value of argument with values
+// of input field flags merged into it
+func (f *FieldCommandInputArgument) Variable() interface{} {
+	if len(f.fields) == 0 {
+		return f.value.value
+	}
+	return mergeValues(f.value.value, f.fields.Unflatten())
+}
+
+// mergeValues merges src into dst, values of src
+// take precedence unless both are objects
+func mergeValues(dst, src interface{}) interface{} {
+	dm, ok := dst.(map[string]interface{})
+	if !ok {
+		return src
+	}
+	sm, ok := src.(map[string]interface{})
+	if !ok {
+		return src
+	}
+	m := make(map[string]interface{}, len(dm)+len(sm))
+	for k, v := range dm {
+		m[k] = v
+	}
+	for k, v := range sm {
+		m[k] = mergeValues(m[k], v)
+	}
+	return m
+}
+
+type inputSetter struct {
+	f *FieldCommandInputArgument
+}
+
+func (i *inputSetter) Set(cmd *cobra.Command, f FieldCommandArgument) {
+	argName := FieldCommandArgName(f)
+	usage := FieldCommandArgType(f) + ", GraphQL value or @file with JSON or YAML, @- reads standard input"
+	cmd.Flags().Var(&i.f.value, argName, usage)
+	i.f.setFieldFlags(cmd.Flags(), argName, "", i.f.input, map[string]bool{i.f.input.Name: true})
+}
+
+// setFieldFlags adds a flag for each input field of t, fields of nested
+// input objects get their own flags unless input type is recursive
+func (f *FieldCommandInputArgument) setFieldFlags(flags *pflag.FlagSet, argName, path string, t introspection.Type, seen map[string]bool) {
+	for _, field := range t.InputFields {
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+		leaf := field.Type.GetOfTypeLeaf()
+		named := leaf.Deref(f.schema.Types)
+		if !named.Valid() {
+			named = leaf
+		}
+		typ := field.Type
+		if typ.NonNull() {
+			typ = *typ.OfType
+		}
+		if named.Input() && !typ.List() && !seen[named.Name] {
+			nested := map[string]bool{named.Name: true}
+			for k := range seen {
+				nested[k] = true
+			}
+			f.setFieldFlags(flags, argName, fieldPath, named, nested)
+			continue
+		}
+		usage := fmt.Sprintf("Input field of type %s", field.Type.GoString())
+		if field.Type.NonNull() && field.DefaultValue == "" {
+			usage += ", required"
+		}
+		flags.Var(&inputFieldValue{
+			f:     f,
+			path:  fieldPath,
+			typ:   field.Type,
+			named: named,
+		}, argName+"."+fieldPath, usage)
+	}
+}
+
+// argChanged returns true if argument flag or any
+// of its input field flags were set
+func argChanged(flags *pflag.FlagSet, argName string) bool {
+	changed := false
+	flags.Visit(func(flag *pflag.Flag) {
+		changed = changed || flag.Name == argName || strings.HasPrefix(flag.Name, argName+".")
+	})
+	return changed
+}
+
+// inputValue is a value of input object argument flag,
+// either a GraphQL literal or a file prefixed with @
+type inputValue struct {
+	literalValue
+}
+
+func (i *inputValue) Set(s string) error {
+	if !strings.HasPrefix(s, "@") {
+		return i.literalValue.Set(s)
+	}
+	v, err := readInputFile(s[1:])
+	if err != nil {
+		return err
+	}
+	i.literal = s
+	i.value = v
+	return nil
+}
+
+// readInputFile reads value of input object from file, files with
+// .json extension are read as JSON, anything else as YAML, which
+// also accepts JSON. If name is "-", value is read from standard input
+func readInputFile(name string) (interface{}, error) {
+	var b []byte
+	var err error
+	if name == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if filepath.Ext(name) == ".json" {
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			return nil, fmt.Errorf("invalid JSON in %s: %v", name, err)
+		}
+		return v, nil
+	}
+	if err := yaml.Unmarshal(b, &v); err != nil {
+		return nil, fmt.Errorf("invalid YAML in %s: %v", name, err)
+	}
+	return yamlValue(v), nil
+}
+
+// yamlValue converts YAML mappings to objects
+// with string keys, as used by JSON
+func yamlValue(v interface{}) interface{} {
+	switch vt := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(vt))
+		for k, mv := range vt {
+			m[fmt.Sprint(k)] = yamlValue(mv)
+		}
+		return m
+	case []interface{}:
+		for i, item := range vt {
+			vt[i] = yamlValue(item)
+		}
+	}
+	return v
+}
+
+// inputFieldValue is a value of input field flag
+type inputFieldValue struct {
+	f    *FieldCommandInputArgument
+	path string
+	raw  string
+	typ  introspection.Type
+	// named is definition of leaf type of typ
+	named introspection.Type
+}
+
+func (i *inputFieldValue) String() string {
+	return i.raw
+}
+
+func (i *inputFieldValue) Type() string {
+	return i.typ.GoString()
+}
+
+func (i *inputFieldValue) Set(s string) error {
+	v, err := i.parse(s, i.typ)
+	if err != nil {
+		return err
+	}
+	i.raw = s
+	i.f.fields[i.path] = v
+	return nil
+}
+
+func (i *inputFieldValue) enum() *enumValue {
+	return &enumValue{
+		enumName: i.named.Name,
+		values:   i.named.EnumValues,
+	}
+}
+
+// completions returns values of enum input fields
+func (i *inputFieldValue) completions() []completion {
+	if i.named.Enum() {
+		return i.enum().completions()
+	}
+	return nil
+}
+
+// parse converts flag value to a value of type t, list items are
+// separated with comma and input objects are GraphQL literals
+func (i *inputFieldValue) parse(s string, t introspection.Type) (interface{}, error) {
+	switch {
+	case i.named.Input():
+		return introspection.ParseValue(s)
+	case t.NonNull():
+		return i.parse(s, *t.OfType)
+	case t.List():
+		items := strings.Split(s, ",")
+		list := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			v, err := i.parse(item, *t.OfType)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	}
+	switch i.named.Name {
+	case "Int":
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Int value %s", s)
+		}
+		return n, nil
+	case "Float":
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Float value %s", s)
+		}
+		return n, nil
+	case "Boolean":
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Boolean value %s", s)
+		}
+		return b, nil
+	case "String", "ID":
+		return s, nil
+	}
+	if i.named.Enum() {
+		return s, i.enum().check(s)
+	}
+	// custom scalars are sent as JSON if
+	// possible or as string otherwise
+	var v interface{}
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return s, nil
+	}
+	return v, nil
+}
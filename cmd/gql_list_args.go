@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/slothking-online/gql/client"
+	"github.com/slothking-online/gql/introspection"
+)
+
+// represents GraphQL field argument of list type, flag can be
+// repeated to add elements or set to an array, for example
+// --arg-ids 1 --arg-ids 2 or --arg-ids '[1, 2]'
+type FieldCommandListArgument struct {
+	// element type of the list
+	FieldCommandArgument
+	value listValue
+}
+
+func (f *FieldCommandListArgument) String() string {
+	return f.value.String()
+}
+
+func (f *FieldCommandListArgument) Value() interface{} {
+	return &f.value
+}
+
+func (f *FieldCommandListArgument) Variable() interface{} {
+	return f.value.items
+}
+
+func (f *FieldCommandListArgument) Type() string {
+	return "[" + f.FieldCommandArgument.Type() + "]"
+}
+
+// listValue is a value of list argument flag
+type listValue struct {
+	// of is element type of the list
+	of    FieldCommandArgument
+	items []interface{}
+}
+
+func (l *listValue) String() string {
+	if l.items == nil {
+		return ""
+	}
+	b, err := json.Marshal(l.items)
+	if err != nil {
+		// fall back to plain list of values
+		items := make([]string, 0, len(l.items))
+		for _, item := range l.items {
+			items = append(items, fmt.Sprint(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return string(b)
+}
+
+// Type returns element type of the list
+func (l *listValue) Type() string {
+	return l.of.Type()
+}
+
+// completions returns values of list elements
+// that are enum values
+func (l *listValue) completions() []completion {
+	of, _ := unwrapNonNull(l.of)
+	if e, ok := of.(*FieldCommandEnumArgument); ok {
+		return e.value.completions()
+	}
+	return nil
+}
+
+// Set adds an element to the list, arrays add all of their
+// elements instead. Elements of string lists can start with [,
+// so only JSON arrays are arrays for them
+func (l *listValue) Set(s string) error {
+	if l.items == nil {
+		l.items = []interface{}{}
+	}
+	if strings.HasPrefix(strings.TrimSpace(s), "[") {
+		v, err := parseJSON(s)
+		if err != nil && !stringElement(l.of) {
+			v, err = introspection.ParseValue(s)
+		}
+		if err == nil {
+			items, err := coerceValue(&FieldCommandListArgument{FieldCommandArgument: l.of}, v)
+			if err == nil {
+				l.items = append(l.items, items.([]interface{})...)
+				return nil
+			}
+			// array can still be a single
+			// element of nested list
+			if !listArgument(l.of) {
+				return err
+			}
+		}
+	}
+	v, err := elementValue(l.of, s)
+	if err != nil {
+		return err
+	}
+	l.items = append(l.items, v)
+	return nil
+}
+
+// unwrapNonNull returns argument wrapped by non null
+// argument and true if it was wrapped
+func unwrapNonNull(f FieldCommandArgument) (FieldCommandArgument, bool) {
+	switch ft := f.(type) {
+	case *FieldCommandNonNullArgument:
+		return ft.FieldCommandArgument, true
+	case *fieldCommandNonNullArgument:
+		return ft.FieldCommandArgument, true
+	}
+	return f, false
+}
+
+func listArgument(f FieldCommandArgument) bool {
+	f, _ = unwrapNonNull(f)
+	_, ok := f.(*FieldCommandListArgument)
+	return ok
+}
+
+// stringElement returns true if any string
+// is a valid value of argument f
+func stringElement(f FieldCommandArgument) bool {
+	f, _ = unwrapNonNull(f)
+	switch f.(type) {
+	case *FieldCommandStringArgument, *FieldCommandIDArgument,
+		*FieldCommandUploadArgument, *FieldCommandCustomScalarArgument:
+		return true
+	}
+	return false
+}
+
+// parseJSON parses JSON value, keeping numbers as json.Number
+func parseJSON(s string) (interface{}, error) {
+	var v interface{}
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("invalid JSON value %s", s)
+	}
+	return v, nil
+}
+
+// parseLiteral parses JSON or GraphQL value
+func parseLiteral(s string) (interface{}, error) {
+	if v, err := parseJSON(s); err == nil {
+		return v, nil
+	}
+	return introspection.ParseValue(s)
+}
+
+// elementValue converts flag value of a single
+// list element to a value of argument f
+func elementValue(f FieldCommandArgument, s string) (interface{}, error) {
+	f, _ = unwrapNonNull(f)
+	switch ft := f.(type) {
+	case *FieldCommandIntArgument:
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Int value %s", s)
+		}
+		return n, nil
+	case *FieldCommandFloatArgument:
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Float value %s", s)
+		}
+		return n, nil
+	case *FieldCommandBooleanArgument:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Boolean value %s", s)
+		}
+		return b, nil
+	case *FieldCommandStringArgument, *FieldCommandIDArgument:
+		return s, nil
+	case *FieldCommandEnumArgument:
+		return s, ft.value.check(s)
+	case *FieldCommandUploadArgument:
+		return client.FileUpload(strings.TrimPrefix(s, "@")), nil
+	case *FieldCommandInputArgument:
+		if strings.HasPrefix(s, "@") {
+			return readInputFile(s[1:])
+		}
+	case *FieldCommandCustomScalarArgument:
+		// custom scalars are lenient, same as
+		// when they are not list elements
+		v, err := parseLiteral(s)
+		if err != nil {
+			return s, nil
+		}
+		return v, nil
+	}
+	v, err := parseLiteral(s)
+	if err != nil {
+		return nil, err
+	}
+	return coerceValue(f, v)
+}
+
+// coerceValue checks parsed value v against argument
+// f, converting it to a value of query variable
+func coerceValue(f FieldCommandArgument, v interface{}) (interface{}, error) {
+	f, nonNull := unwrapNonNull(f)
+	if v == nil {
+		if nonNull {
+			return nil, fmt.Errorf("expected value of type %s!, found null", f.Type())
+		}
+		return nil, nil
+	}
+	var ok bool
+	switch ft := f.(type) {
+	case *FieldCommandListArgument:
+		if list, isList := v.([]interface{}); isList {
+			items := make([]interface{}, 0, len(list))
+			for _, item := range list {
+				iv, err := coerceValue(ft.FieldCommandArgument, item)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, iv)
+			}
+			return items, nil
+		}
+	case *FieldCommandIntArgument:
+		if n, isNumber := v.(json.Number); isNumber {
+			if i, err := n.Int64(); err == nil && i >= math.MinInt32 && i <= math.MaxInt32 {
+				return i, nil
+			}
+		}
+	case *FieldCommandFloatArgument:
+		if n, isNumber := v.(json.Number); isNumber {
+			if fl, err := n.Float64(); err == nil {
+				return fl, nil
+			}
+		}
+	case *FieldCommandBooleanArgument:
+		_, ok = v.(bool)
+	case *FieldCommandStringArgument:
+		_, ok = v.(string)
+	case *FieldCommandEnumArgument:
+		if s, isString := v.(string); isString {
+			return s, ft.value.check(s)
+		}
+	case *FieldCommandIDArgument:
+		switch vt := v.(type) {
+		case string:
+			return vt, nil
+		case json.Number:
+			if _, err := vt.Int64(); err == nil {
+				return vt.String(), nil
+			}
+		}
+	case *FieldCommandUploadArgument:
+		if path, isString := v.(string); isString {
+			return client.FileUpload(strings.TrimPrefix(path, "@")), nil
+		}
+	default:
+		// input objects are validated against
+		// schema and custom scalars are not checked
+		return v, nil
+	}
+	if !ok {
+		found, _ := json.Marshal(v)
+		return nil, fmt.Errorf("expected value of type %s, found %s", f.Type(), found)
+	}
+	return v, nil
+}
@@ -57,6 +57,9 @@ type FieldCommand struct {
 	Args     []FieldCommandArgument
 	MaxDepth int
 	Fields   []string
+	// QueryBuilder collects variables of arguments
+	// sent as query variables, optional
+	QueryBuilder *QueryBuilder
 }
 
 func shortDesc(field introspection.Field, args []FieldCommandArgument) string {
@@ -77,7 +80,7 @@ func NewFieldCommand(
 	RunE func(*cobra.Command, []string) error,
 	PreRun func(*cobra.Command, []string),
 ) *FieldCommand {
-	args := GetFieldArguments(field)
+	args := GetFieldArguments(field, schema)
 	fc := &FieldCommand{
 		Field:  field,
 		Schema: schema,
@@ -94,7 +97,7 @@ func NewFieldCommand(
 		Set(fc.Command, arg)
 	}
 	fc.Command.Flags().IntVar(&fc.MaxDepth, "max-depth", 0, "resolve this field up to max-depth")
-	fc.Command.Flags().StringArrayVar(&fc.Fields, "fields", nil, "additional fields to resolve aside from the next one in resolve path")
+	fc.Command.Flags().StringArrayVar(&fc.Fields, "fields", nil, "additional selections on this field aside from the next one in resolve path, such as name, alias:name or owner { login }, can be repeated")
 	return fc
 }
 
@@ -106,11 +109,14 @@ func (f *FieldCommand) ArgsString() string {
 	sep := "("
 	for _, arg := range f.Args {
 		// if not changed assume not set
-		flag := f.Command.Flags().Lookup(FieldCommandArgName(arg))
-		if !flag.Changed {
+		if !argChanged(f.Command.Flags(), FieldCommandArgName(arg)) {
 			continue
 		}
-		fmt.Fprintf(buf, "%s%s: %s", sep, arg.Name(), arg.String())
+		value := arg.String()
+		if v, ok := fieldCommandVariable(arg); ok && f.QueryBuilder != nil {
+			value = "$" + f.QueryBuilder.Set(arg.Name(), arg.Type(), v.Variable())
+		}
+		fmt.Fprintf(buf, "%s%s: %s", sep, arg.Name(), value)
 		sep = ", "
 	}
 	if buf.Len() != 0 {
@@ -119,6 +125,49 @@ func (f *FieldCommand) ArgsString() string {
 	return buf.String()
 }
 
+// ValidateArgs checks values of input object arguments
+// against their input types
+func (f *FieldCommand) ValidateArgs() error {
+	for i, arg := range f.Args {
+		def := f.Field.Args[i]
+		if !def.Type.GetOfTypeLeaf().Deref(f.Schema.Types).Input() {
+			continue
+		}
+		if !argChanged(f.Command.Flags(), FieldCommandArgName(arg)) {
+			if def.Type.NonNull() {
+				return fmt.Errorf(`argument "%s" of required type "%s" was not provided`, def.Name, def.Type.GoString())
+			}
+			continue
+		}
+		v, ok := fieldCommandVariable(arg)
+		if !ok {
+			continue
+		}
+		if err := f.Schema.ValidateValue(def.Name, v.Variable(), def.Type); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateFields checks selections added with --fields
+// against type of the field
+func (f *FieldCommand) ValidateFields() error {
+	typeName := f.Field.Type.GetOfTypeLeaf().Name
+	for _, selection := range f.Fields {
+		errs := f.Schema.ValidateSelection(typeName, selection)
+		if len(errs) == 0 {
+			continue
+		}
+		msgs := make([]string, 0, len(errs))
+		for _, err := range errs {
+			msgs = append(msgs, err.Message)
+		}
+		return fmt.Errorf("invalid --fields %s: %s", selection, strings.Join(msgs, "\n"))
+	}
+	return nil
+}
+
 func (f *FieldCommand) solve(sf introspection.Field, depth int, withArgs bool) string {
 	sf.Type = sf.Type.GetOfTypeLeaf()
 	realType := sf.Type
@@ -153,6 +202,9 @@ func (f *FieldCommand) solve(sf introspection.Field, depth int, withArgs bool) s
 			}
 		}
 	}
+	if withArgs {
+		fields = append(fields, f.Fields...)
+	}
 	if len(fields) == 0 {
 		return ""
 	}
@@ -164,7 +216,7 @@ func (f *FieldCommand) solve(sf introspection.Field, depth int, withArgs bool) s
 
 func (f *FieldCommand) BuildQuery() string {
 	if f.Field.Type.Enum() || f.Field.Type.Scalar() {
-		return f.Field.Name
+		return f.Field.Name + f.ArgsString()
 	}
 	var solved string
 	// Keep increasing depth until we get atleast some kind
@@ -192,6 +244,8 @@ type GraphQLCommandConfig struct {
 type QueryBuilder struct {
 	query     string
 	variables map[string]interface{}
+	// variable definitions of operation
+	definitions []string
 }
 
 type GraphQLCommand struct {
@@ -208,12 +262,35 @@ func (qb *QueryBuilder) Wrap(name string, extra ...string) {
 	}
 }
 
+// Query returns built query with variable definitions
+// added to the root operation
 func (qb *QueryBuilder) Query() string {
-	return qb.query
+	if len(qb.definitions) == 0 {
+		return qb.query
+	}
+	defs := "(" + strings.Join(qb.definitions, ", ") + ")"
+	if i := strings.Index(qb.query, " "); i != -1 {
+		return qb.query[:i] + defs + qb.query[i:]
+	}
+	return qb.query + defs
 }
 
-func (qb *QueryBuilder) Set(name string, value interface{}) {
-
+// Set adds a variable of GraphQL type typ to the query, returning
+// name of the variable which is unique within the query
+func (qb *QueryBuilder) Set(name, typ string, value interface{}) string {
+	if qb.variables == nil {
+		qb.variables = make(map[string]interface{})
+	}
+	varName := name
+	for i := 2; ; i++ {
+		if _, ok := qb.variables[varName]; !ok {
+			break
+		}
+		varName = fmt.Sprintf("%s%d", name, i)
+	}
+	qb.variables[varName] = value
+	qb.definitions = append(qb.definitions, fmt.Sprintf("$%s: %s", varName, typ))
+	return varName
 }
 
 func (qb *QueryBuilder) Variables() map[string]interface{} {
@@ -233,7 +310,7 @@ func (g *GraphQLCommand) FieldPreRun(c *cobra.Command, args []string) {
 		// Leaf field
 		g.QueryBuilder.Wrap(g.FieldCommand.BuildQuery())
 	} else {
-		g.QueryBuilder.Wrap(g.FieldCommand.Field.Name + g.FieldCommand.ArgsString())
+		g.QueryBuilder.Wrap(g.FieldCommand.Field.Name+g.FieldCommand.ArgsString(), g.FieldCommand.Fields...)
 	}
 
 	// Traverse parent preruns, to build full query.
@@ -271,7 +348,10 @@ func (g *GraphQLCommand) checkValid() error {
 	if g.isSimple() && len(g.Config.Path) != 0 {
 		return errors.New("enum and scalar leaf fields do not accept any more arguments")
 	}
-	return nil
+	if err := g.FieldCommand.ValidateArgs(); err != nil {
+		return err
+	}
+	return g.FieldCommand.ValidateFields()
 }
 
 func (g *GraphQLCommand) BuildSubCommands() error {
@@ -333,6 +413,7 @@ func NewGraphQLCommand(config GraphQLCommandConfig) GraphQLCommand {
 		cmd.RunE,
 		cmd.FieldPreRun,
 	)
+	cmd.FieldCommand.QueryBuilder = config.QueryBuilder
 	return cmd
 }
 
@@ -344,9 +425,20 @@ func NewGraphQLRootCommands(config GraphQLRootConfig) (GraphQLRootCommands, erro
 		Config:       config,
 		QueryBuilder: config.QueryBuilder,
 	}
+	if config.SchemaFile != "" && !config.ForceRemote {
+		// schema file replaces introspection, so that
+		// endpoints with introspection disabled can be used
+		if err := gqlRoot.newCommandFromFile(); err != nil {
+			return GraphQLRootCommands{}, err
+		}
+		return gqlRoot, nil
+	}
 	var err error
 	if config.Schema != nil && !config.ForceRemote {
 		err = gqlRoot.newCommandFromSchema()
+		if err == nil {
+			return gqlRoot, nil
+		}
 	}
 	if config.Schema == nil || config.ForceRemote || err != nil {
 		err = gqlRoot.newCommandFromRemote()
@@ -366,7 +458,34 @@ func NewGraphQLRootCommands(config GraphQLRootConfig) (GraphQLRootCommands, erro
 
 // create command from user supplied schema
 func (g *GraphQLRootCommands) newCommandFromSchema() error {
-	return nil
+	schema, err := introspection.FromGraphQLSchema(g.Config.Schema)
+	if err != nil {
+		return err
+	}
+	return g.newCommandFromIntrospection(schema)
+}
+
+// readSchemaFile reads schema from file, either SDL
+// or JSON result of introspection query
+func readSchemaFile(fn string) (introspection.Schema, error) {
+	b, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return introspection.Schema{}, err
+	}
+	schema, err := introspection.Parse(b)
+	if err != nil {
+		return introspection.Schema{}, fmt.Errorf("%s: %v", fn, err)
+	}
+	return schema, nil
+}
+
+// create command from schema file
+func (g *GraphQLRootCommands) newCommandFromFile() error {
+	schema, err := readSchemaFile(g.Config.SchemaFile)
+	if err != nil {
+		return err
+	}
+	return g.newCommandFromIntrospection(schema)
 }
 
 // replace all non alphanumeric characters with "-"
@@ -374,8 +493,8 @@ func endpointCacheFn(cfg GraphQLRootConfig) string {
 	return regexp.MustCompile("[^a-zA-Z0-9]").ReplaceAllString(cfg.Endpoint, "-")
 }
 
-// get default system cache path
-func (g *GraphQLRootCommands) cacheFilePath() (string, error) {
+// get default system cache directory for gql
+func cacheDir() (string, error) {
 	cDir, err := os.UserCacheDir()
 	if err != nil {
 		return "", err
@@ -389,6 +508,15 @@ func (g *GraphQLRootCommands) cacheFilePath() (string, error) {
 			return "", err
 		}
 	}
+	return cDir, nil
+}
+
+// get default system cache path
+func (g *GraphQLRootCommands) cacheFilePath() (string, error) {
+	cDir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
 	return filepath.Join(cDir, endpointCacheFn(g.Config)), nil
 }
 
@@ -450,14 +578,14 @@ func (g *GraphQLRootCommands) newCommandFromRemote() error {
 		// for now
 		return nil
 	}
-	cli := client.New(client.Config{
-		Endpoint: g.Config.Endpoint,
-	})
+	cli := newClient(g.Config.Endpoint, g.Config.ConnectTimeout)
 	httpHeader := make(http.Header)
 	for k, v := range g.Config.Header {
 		httpHeader.Add(k, v)
 	}
-	schema, err := introspection.GetSchemaTypes(cli, httpHeader)
+	ctx, cancel := withTimeout(g.Config.Timeout)
+	defer cancel()
+	schema, err := introspection.GetSchemaContext(ctx, cli, httpHeader)
 	if err != nil {
 		return err
 	}
@@ -485,12 +613,21 @@ type GraphQLRootConfig struct {
 	Path []string
 	// optional: local schema
 	Schema *graphql.Schema
+	// optional: path to local schema file, in SDL or
+	// introspection JSON, used instead of endpoint
+	SchemaFile string
+	// optional: limit of time spent on schema introspection
+	Timeout time.Duration
+	// optional: limit of time spent on connecting to endpoint
+	// during schema introspection
+	ConnectTimeout time.Duration
 }
 
 func (g *GraphQLRootCommands) rootCmd(
 	schema introspection.Schema,
 	field introspection.Field,
 	path []string,
+	run func(*cobra.Command, []string) error,
 ) GraphQLCommand {
 	cmd := NewGraphQLCommand(GraphQLCommandConfig{
 		Field:        field,
@@ -498,7 +635,14 @@ func (g *GraphQLRootCommands) rootCmd(
 		QueryBuilder: g.Config.QueryBuilder,
 		Schema:       schema,
 	})
-	cmd.FieldCommand.Command.RunE = g.RunE
+	// root command runs the query, so it is
+	// validated here instead of by GraphQLCommand.RunE
+	cmd.FieldCommand.Command.RunE = func(c *cobra.Command, args []string) error {
+		if err := cmd.checkValid(); err != nil {
+			return err
+		}
+		return run(c, args)
+	}
 	return cmd
 }
 
@@ -521,6 +665,7 @@ func (g *GraphQLRootCommands) newCommandFromIntrospection(schema introspection.S
 				Description: rootQueryOpDesc,
 			},
 			path,
+			g.RunE,
 		)
 		g.Query.FieldCommand.Short = rootQueryOpDescShort
 	}
@@ -534,6 +679,7 @@ func (g *GraphQLRootCommands) newCommandFromIntrospection(schema introspection.S
 				Description: `TODO`,
 			},
 			path,
+			g.RunE,
 		)
 		g.Mutation.FieldCommand.Short = "Quick graphql mutation operation"
 	}
@@ -547,6 +693,7 @@ func (g *GraphQLRootCommands) newCommandFromIntrospection(schema introspection.S
 				Description: `TODO`,
 			},
 			path,
+			g.SubscribeE,
 		)
 		g.Subscription.FieldCommand.Short = "Quick graphql subscription operation"
 	}
@@ -572,16 +719,35 @@ func (g *GraphQLRootCommands) RunE(c *cobra.Command, args []string) error {
 	for k, v := range g.Config.Header {
 		httpHeader.Add(k, v)
 	}
-	cli := client.New(client.Config{
-		Endpoint: g.Config.Endpoint,
-	})
+	cli := newClient(g.Config.Endpoint, connectTimeout)
 	r := client.Raw{
 		Query:     g.QueryBuilder.Query(),
 		Variables: g.QueryBuilder.Variables(),
 		Header:    httpHeader,
+		Method:    method,
 	}
-	execute(g.Config.Config, cli, r, nil)
-	return nil
+	ctx, cancel := requestContext()
+	defer cancel()
+	return execute(ctx, g.Config.Config, cli, r, nil)
+}
+
+// run function for subscription commands, keeps printing
+// results until remote endpoint completes the subscription
+func (g *GraphQLRootCommands) SubscribeE(c *cobra.Command, args []string) error {
+	httpHeader := make(http.Header)
+	for k, v := range g.Config.Header {
+		httpHeader.Add(k, v)
+	}
+	cli := newClient(g.Config.Endpoint, connectTimeout)
+	r := client.Raw{
+		Query:     g.QueryBuilder.Query(),
+		Variables: g.QueryBuilder.Variables(),
+		Header:    httpHeader,
+		Method:    method,
+	}
+	ctx, cancel := requestContext()
+	defer cancel()
+	return subscribe(ctx, g.Config.Config, cli, r)
 }
 
 func defaultQueryCommand() GraphQLCommand {
@@ -0,0 +1,430 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryBuilder(t *testing.T) {
+	assert := assert.New(t)
+	qb := &QueryBuilder{}
+	qb.Wrap("field(a: $" + qb.Set("a", "Upload!", "x") + ", b: $" + qb.Set("a", "Upload", "y") + ")")
+	qb.Wrap("mutation")
+	assert.Equal("mutation($a: Upload!, $a2: Upload) {  field(a: $a, b: $a2) }", qb.Query())
+	assert.Equal(map[string]interface{}{"a": "x", "a2": "y"}, qb.Variables())
+	qb = &QueryBuilder{}
+	qb.Wrap("field")
+	qb.Wrap("query")
+	assert.Equal("query {  field }", qb.Query())
+}
+
+func TestNewGraphQLRootCommandsFromFile(t *testing.T) {
+	assert := assert.New(t)
+	f, err := ioutil.TempFile("", "schema*.graphql")
+	if !assert.NoError(err) {
+		return
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString("type Query { hello(name: String): String }\ntype Mutation { ping: Boolean }")
+	f.Close()
+	if !assert.NoError(err) {
+		return
+	}
+	root, err := NewGraphQLRootCommands(GraphQLRootConfig{
+		Path:       []string{"query"},
+		SchemaFile: f.Name(),
+	})
+	if !assert.NoError(err) {
+		return
+	}
+	assert.NotNil(root.Query.FieldCommand)
+	assert.NotNil(root.Mutation.FieldCommand)
+	assert.Nil(root.Subscription.FieldCommand)
+	_, ok := root.Schema.FieldForPath([]string{"query", "hello"})
+	assert.True(ok)
+	_, err = NewGraphQLRootCommands(GraphQLRootConfig{
+		SchemaFile: f.Name() + ".missing",
+	})
+	assert.Error(err)
+}
+
+type testCaseFieldArgumentVariables struct {
+	path []string
+	args []string
+	// flags set directly on parent segment commands, cobra
+	// parses command line flags with the leaf command only
+	parentFlags map[string]map[string]string
+	// file with argument value, {file} in args is
+	// replaced with its path
+	file      string
+	fileExt   string
+	query     string
+	variables map[string]interface{}
+	err       bool
+}
+
+func (tt testCaseFieldArgumentVariables) test(t *testing.T) {
+	assert := assert.New(t)
+	f, err := ioutil.TempFile("", "schema*.graphql")
+	if !assert.NoError(err) {
+		return
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(`
+scalar Date
+input Filter { text: String, ids: [ID] }
+type Post { id: ID title: String }
+type User { name: String posts(id: ID, first: Int): [Post] }
+enum Kind { NEWS BLOG }
+input Author { name: String!, friend: Author }
+input PostInput {
+  title: String!
+  labels: [String!]
+  count: Int
+  kind: Kind
+  author: Author
+  related: [PostInput]
+}
+type Query {
+  user(id: ID!): User
+  hello(name: String, filter: Filter, tags: [String!], at: Date): String
+  create(input: PostInput!): String
+  list(ids: [ID!], matrix: [[Int]], kinds: [Kind!]!, flags: [Boolean], counts: [Int], scores: [Float]): String
+}`)
+	f.Close()
+	if !assert.NoError(err) {
+		return
+	}
+	var body struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer srv.Close()
+	root, err := NewGraphQLRootCommands(GraphQLRootConfig{
+		Config:     Config{Out: &bytes.Buffer{}, Err: &bytes.Buffer{}},
+		Endpoint:   srv.URL,
+		Path:       tt.path,
+		SchemaFile: f.Name(),
+	})
+	if !assert.NoError(err) {
+		return
+	}
+	cmd := root.Query.FieldCommand.Command
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	// same as root command of gql, so that each
+	// segment in path parses its own flags
+	cmd.TraverseChildren = true
+	for name, flags := range tt.parentFlags {
+		parent, _, err := cmd.Find([]string{name})
+		if !assert.NoError(err) {
+			return
+		}
+		for flag, value := range flags {
+			assert.NoError(parent.Flags().Set(flag, value))
+		}
+	}
+	args := tt.args
+	if tt.file != "" {
+		input, err := ioutil.TempFile("", "input*"+tt.fileExt)
+		if !assert.NoError(err) {
+			return
+		}
+		defer os.Remove(input.Name())
+		input.WriteString(tt.file)
+		input.Close()
+		args = nil
+		for _, arg := range tt.args {
+			args = append(args, strings.Replace(arg, "{file}", input.Name(), -1))
+		}
+	}
+	cmd.SetArgs(args)
+	err = cmd.Execute()
+	if tt.err {
+		assert.Error(err)
+		return
+	}
+	assert.NoError(err)
+	assert.Equal(tt.query, body.Query)
+	assert.Equal(tt.variables, body.Variables)
+}
+
+func TestFieldArgumentVariables(t *testing.T) {
+	tests := map[string]testCaseFieldArgumentVariables{
+		"EscapedString": {
+			path:      []string{"query", "hello"},
+			args:      []string{"hello", "--arg-name", "a \"q\"\\b\nc"},
+			query:     "query($name: String) {  hello(name: $name) }",
+			variables: map[string]interface{}{"name": "a \"q\"\\b\nc"},
+		},
+		"Literals": {
+			path: []string{"query", "hello"},
+			args: []string{
+				"hello",
+				"--arg-filter", `{text: "x", ids: [1, null]}`,
+				"--arg-tags", `["a", "b"]`,
+				"--arg-at", "2020-01-01",
+			},
+			query: "query($filter: Filter, $tags: [String!], $at: Date) {  hello(filter: $filter, tags: $tags, at: $at) }",
+			variables: map[string]interface{}{
+				"filter": map[string]interface{}{"text": "x", "ids": []interface{}{float64(1), nil}},
+				"tags":   []interface{}{"a", "b"},
+				"at":     "2020-01-01",
+			},
+		},
+		"InvalidLiteral": {
+			path: []string{"query", "hello"},
+			args: []string{"hello", "--arg-filter", "{text: "},
+			err:  true,
+		},
+		"InputFieldFlags": {
+			path: []string{"query", "create"},
+			args: []string{
+				"create",
+				"--arg-input", `{title: "x", count: 1}`,
+				"--arg-input.title=y",
+				"--arg-input.labels=a,b",
+				"--arg-input.author.name=bob",
+				"--arg-input.author.friend", `{name: "alice"}`,
+			},
+			query: "query($input: PostInput!) {  create(input: $input) }",
+			variables: map[string]interface{}{
+				"input": map[string]interface{}{
+					"title":  "y",
+					"count":  float64(1),
+					"labels": []interface{}{"a", "b"},
+					"author": map[string]interface{}{
+						"name":   "bob",
+						"friend": map[string]interface{}{"name": "alice"},
+					},
+				},
+			},
+		},
+		"InputYAMLFile": {
+			path:    []string{"query", "create"},
+			args:    []string{"create", "--arg-input", "@{file}", "--arg-input.kind", "BLOG"},
+			file:    "title: x\nlabels: [a]\nrelated:\n  - title: z\n",
+			fileExt: ".yaml",
+			query:   "query($input: PostInput!) {  create(input: $input) }",
+			variables: map[string]interface{}{
+				"input": map[string]interface{}{
+					"title":   "x",
+					"kind":    "BLOG",
+					"labels":  []interface{}{"a"},
+					"related": []interface{}{map[string]interface{}{"title": "z"}},
+				},
+			},
+		},
+		"InputJSONFile": {
+			path:    []string{"query", "create"},
+			args:    []string{"create", "--arg-input", "@{file}"},
+			file:    `{"title": "x", "count": 2}`,
+			fileExt: ".json",
+			query:   "query($input: PostInput!) {  create(input: $input) }",
+			variables: map[string]interface{}{
+				"input": map[string]interface{}{"title": "x", "count": float64(2)},
+			},
+		},
+		"InputMissing": {
+			path: []string{"query", "create"},
+			args: []string{"create"},
+			err:  true,
+		},
+		"InputMissingField": {
+			path: []string{"query", "create"},
+			args: []string{"create", "--arg-input.count=1"},
+			err:  true,
+		},
+		"InputInvalidEnum": {
+			path: []string{"query", "create"},
+			args: []string{"create", "--arg-input.title=x", "--arg-input.kind=VLOG"},
+			err:  true,
+		},
+		"InputInvalidInt": {
+			path: []string{"query", "create"},
+			args: []string{"create", "--arg-input.title=x", "--arg-input.count=x"},
+			err:  true,
+		},
+		"RepeatedList": {
+			path: []string{"query", "list"},
+			args: []string{
+				"list",
+				"--arg-ids", "1", "--arg-ids", "a b",
+				"--arg-kinds", "NEWS",
+				"--arg-flags", "[true, null]", "--arg-flags", "false",
+			},
+			query: "query($ids: [ID!], $kinds: [Kind!]!, $flags: [Boolean]) {  list(ids: $ids, kinds: $kinds, flags: $flags) }",
+			variables: map[string]interface{}{
+				"ids":   []interface{}{"1", "a b"},
+				"kinds": []interface{}{"NEWS"},
+				"flags": []interface{}{true, nil, false},
+			},
+		},
+		"ArrayList": {
+			path:  []string{"query", "list"},
+			args:  []string{"list", "--arg-ids", `["1", 2]`, "--arg-kinds", "[NEWS, BLOG]"},
+			query: "query($ids: [ID!], $kinds: [Kind!]!) {  list(ids: $ids, kinds: $kinds) }",
+			variables: map[string]interface{}{
+				"ids":   []interface{}{"1", "2"},
+				"kinds": []interface{}{"NEWS", "BLOG"},
+			},
+		},
+		"NestedList": {
+			path: []string{"query", "list"},
+			args: []string{
+				"list",
+				"--arg-kinds", "BLOG",
+				"--arg-matrix", "[1, 2]", "--arg-matrix", "[[3], null]",
+			},
+			query: "query($matrix: [[Int]], $kinds: [Kind!]!) {  list(matrix: $matrix, kinds: $kinds) }",
+			variables: map[string]interface{}{
+				"matrix": []interface{}{[]interface{}{float64(1), float64(2)}, []interface{}{float64(3)}, nil},
+				"kinds":  []interface{}{"BLOG"},
+			},
+		},
+		"ListNullElement": {
+			path: []string{"query", "list"},
+			args: []string{"list", "--arg-kinds", "BLOG", "--arg-ids", "[1, null]"},
+			err:  true,
+		},
+		"ListInvalidElement": {
+			path: []string{"query", "list"},
+			args: []string{"list", "--arg-kinds", "BLOG", "--arg-matrix", "[[1.5]]"},
+			err:  true,
+		},
+		"ListDecimalInt": {
+			path:  []string{"query", "list"},
+			args:  []string{"list", "--arg-kinds", "BLOG", "--arg-counts", "010", "--arg-counts", "-2147483648"},
+			query: "query($kinds: [Kind!]!, $counts: [Int]) {  list(kinds: $kinds, counts: $counts) }",
+			variables: map[string]interface{}{
+				"kinds":  []interface{}{"BLOG"},
+				"counts": []interface{}{float64(10), float64(-2147483648)},
+			},
+		},
+		"ListArrayIntOutOfRange": {
+			path: []string{"query", "list"},
+			args: []string{"list", "--arg-kinds", "BLOG", "--arg-counts", "[1, 3000000000]"},
+			err:  true,
+		},
+		"ListBracketedString": {
+			path:  []string{"query", "hello"},
+			args:  []string{"hello", "--arg-tags", "[WIP]", "--arg-tags", `["a", "b"]`, "--arg-tags", "[x, y]"},
+			query: "query($tags: [String!]) {  hello(tags: $tags) }",
+			variables: map[string]interface{}{
+				"tags": []interface{}{"[WIP]", "a", "b", "[x, y]"},
+			},
+		},
+		"ListHexInt": {
+			path: []string{"query", "list"},
+			args: []string{"list", "--arg-kinds", "BLOG", "--arg-counts", "0x10"},
+			err:  true,
+		},
+		"ListIntOutOfRange": {
+			path: []string{"query", "list"},
+			args: []string{"list", "--arg-kinds", "BLOG", "--arg-counts", "2147483648"},
+			err:  true,
+		},
+		"ListRequired": {
+			path: []string{"query", "list"},
+			args: []string{"list", "--arg-ids", "1"},
+			err:  true,
+		},
+		"UniqueNames": {
+			path:        []string{"query", "user", "posts"},
+			parentFlags: map[string]map[string]string{"user": {"arg-id": "1"}},
+			args:        []string{"user", "posts", "--arg-id", "2", "--arg-first", "3"},
+			query:       "query($id: ID, $first: Int, $id2: ID!) {  user(id: $id2) {  posts(id: $id, first: $first) { id title } } }",
+			variables: map[string]interface{}{
+				"id":    "2",
+				"first": float64(3),
+				"id2":   "1",
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.test)
+	}
+}
+
+func TestFieldSelections(t *testing.T) {
+	tests := map[string]testCaseFieldArgumentVariables{
+		"Leaf": {
+			path: []string{"query", "user"},
+			args: []string{
+				"user", "--arg-id", "1",
+				"--fields", "n:name",
+				"--fields", "a:posts(first: 1) { id }",
+				"--fields", "b:posts(first: 2) { title }",
+			},
+			query: "query($id: ID!) {  user(id: $id) { name n:name a:posts(first: 1) { id } b:posts(first: 2) { title } } }",
+			variables: map[string]interface{}{
+				"id": "1",
+			},
+		},
+		"Segments": {
+			path: []string{"query", "user", "posts"},
+			args: []string{
+				"--fields", "me: user(id: 2) { name }",
+				"user", "--arg-id", "1", "--fields", "name", "--fields", "first:posts(first: 1) { title }",
+				"posts", "--arg-first", "2",
+			},
+			query: "query($first: Int, $id: ID!) { me: user(id: 2) { name } user(id: $id) { name first:posts(first: 1) { title } posts(first: $first) { id title } } }",
+			variables: map[string]interface{}{
+				"id":    "1",
+				"first": float64(2),
+			},
+		},
+		"UnknownField": {
+			path: []string{"query", "user", "posts"},
+			args: []string{"user", "--arg-id", "1", "--fields", "nam", "posts"},
+			err:  true,
+		},
+		"UnknownRootField": {
+			path: []string{"query", "hello"},
+			args: []string{"--fields", "helo", "hello"},
+			err:  true,
+		},
+		"MissingSelection": {
+			path: []string{"query", "user", "posts"},
+			args: []string{"user", "--arg-id", "1", "posts", "--fields", "posts"},
+			err:  true,
+		},
+		"Injected": {
+			path: []string{"query", "user"},
+			args: []string{"user", "--arg-id", "1", "--fields", "name } mutation { x"},
+			err:  true,
+		},
+		"ScalarLeaf": {
+			path: []string{"query", "hello"},
+			args: []string{"hello", "--fields", "name"},
+			err:  true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.test)
+	}
+}
+
+func TestListValueString(t *testing.T) {
+	assert := assert.New(t)
+	l := &listValue{of: &FieldCommandFloatArgument{name: "scores"}}
+	assert.Equal("", l.String())
+	assert.NoError(l.Set("1.5"))
+	assert.Equal("[1.5]", l.String())
+	// values that are not valid JSON are still printed
+	assert.NoError(l.Set("NaN"))
+	assert.Equal("[1.5, NaN]", l.String())
+}
@@ -17,13 +17,15 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
 var (
-	noCache bool
+	noCache    bool
+	schemaFile string
 )
 
 // find GraphQL endpoint option and query path
@@ -46,6 +48,10 @@ func Peek(
 	}
 	endpointFlag(endpoint, flagset)
 	headersFlag(header, flagset)
+	noCacheFlag(flagset)
+	schemaFlag(flagset)
+	timeoutFlags(flagset)
+	retryFlags(flagset)
 	if err := flagset.Parse(cargs); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 	}
@@ -79,10 +85,27 @@ func noCacheFlag(flags *pflag.FlagSet) {
 	)
 }
 
+func schemaFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&schemaFile,
+		"schema",
+		"",
+		"use schema from SDL or introspection JSON file instead of introspecting endpoint",
+	)
+}
+
 type IntrospectionCommandConfig struct {
 	Path     []string
 	Endpoint string
 	Header   Header
+	// SchemaFile is a local schema used instead
+	// of endpoint introspection
+	SchemaFile string
+	// Timeout limits time spent on schema introspection
+	Timeout time.Duration
+	// ConnectTimeout limits time spent on connecting
+	// to endpoint during schema introspection
+	ConnectTimeout time.Duration
 }
 
 type IntrospectionCommand struct {
@@ -97,7 +120,13 @@ func (i *IntrospectionCommand) appendDyn(header Header, endpoint *string, cmd *F
 		requiredEndpointFlag(endpoint, cmd.Flags())
 		formatFlag(cmd.PersistentFlags())
 		noCacheFlag(cmd.Flags())
+		schemaFlag(cmd.Flags())
 		headersFlag(header, cmd.Flags())
+		timeoutFlags(cmd.PersistentFlags())
+		retryFlags(cmd.PersistentFlags())
+		methodFlag(cmd.PersistentFlags())
+		persistedFlag(cmd.PersistentFlags())
+		incrementalFlag(cmd.PersistentFlags())
 	}
 }
 
@@ -114,17 +143,23 @@ func NewIntrospectionCommand(config IntrospectionCommandConfig) IntrospectionCom
 	header := introspectionCmd.Config.Header
 	var err error
 	introspectionCmd.GraphQLRootCommands, err = NewGraphQLRootCommands(GraphQLRootConfig{
-		Endpoint: *endpoint,
-		Path:     config.Path,
-		Header:   header,
+		Endpoint:       *endpoint,
+		Path:           config.Path,
+		Header:         header,
+		SchemaFile:     config.SchemaFile,
+		Timeout:        config.Timeout,
+		ConnectTimeout: config.ConnectTimeout,
 	})
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 	introspectionCmd.appendDyn(header, endpoint, introspectionCmd.Query.FieldCommand)
 	introspectionCmd.appendDyn(header, endpoint, introspectionCmd.Mutation.FieldCommand)
 	introspectionCmd.appendDyn(header, endpoint, introspectionCmd.Subscription.FieldCommand)
+	if introspectionCmd.Subscription.FieldCommand != nil {
+		transportFlag(introspectionCmd.Subscription.FieldCommand.PersistentFlags())
+	}
 	introspectionCmd.AddCommand(NewFieldsCommand(introspectionCmd.GraphQLRootCommands.Schema))
 	introspectionCmd.AddCommand(NewArgsCommand(
 		ArgsCommandConfig{
@@ -133,6 +168,9 @@ func NewIntrospectionCommand(config IntrospectionCommandConfig) IntrospectionCom
 	).Command)
 	requiredEndpointFlag(endpoint, introspectionCmd.Flags())
 	noCacheFlag(introspectionCmd.Flags())
+	schemaFlag(introspectionCmd.Flags())
 	headersFlag(header, introspectionCmd.Flags())
+	timeoutFlags(introspectionCmd.Flags())
+	retryFlags(introspectionCmd.Flags())
 	return introspectionCmd
 }
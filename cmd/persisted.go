@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/slothking-online/gql/client"
+	"github.com/spf13/pflag"
+)
+
+func persistedFlag(flags *pflag.FlagSet) {
+	flags.BoolVar(
+		&persisted,
+		"persisted",
+		false,
+		"use automatic persisted queries, sending only query hash if it is known to the endpoint, with GET for queries unless --method is set",
+	)
+}
+
+// persistedCache keeps hashes of persisted queries
+// registered on endpoint in user cache directory,
+// so that they are reused between invocations
+type persistedCache struct {
+	mu     sync.Mutex
+	path   string
+	hashes map[string]struct{}
+}
+
+// newPersistedCache loads persisted query hashes known
+// for endpoint. If cache file cannot be used, hashes are
+// kept in memory only
+func newPersistedCache(endpoint string) client.PersistedQueryCache {
+	p := &persistedCache{
+		hashes: make(map[string]struct{}),
+	}
+	cDir, err := cacheDir()
+	if err != nil {
+		return p
+	}
+	// hashes are kept in their own directory, so that
+	// file names cannot collide with schema cache
+	cDir = filepath.Join(cDir, "persisted")
+	if err := os.MkdirAll(cDir, os.ModeDir|os.FileMode(0740)); err != nil {
+		return p
+	}
+	sum := sha256.Sum256([]byte(endpoint))
+	p.path = filepath.Join(cDir, hex.EncodeToString(sum[:]))
+	f, err := os.Open(p.path)
+	if err != nil {
+		return p
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if hash := scanner.Text(); hash != "" {
+			p.hashes[hash] = struct{}{}
+		}
+	}
+	return p
+}
+
+func (p *persistedCache) Known(hash string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.hashes[hash]
+	return ok
+}
+
+func (p *persistedCache) Add(hash string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.hashes[hash]; ok {
+		return
+	}
+	p.hashes[hash] = struct{}{}
+	if p.path == "" {
+		return
+	}
+	// TODO: log some kind of warning on errors?
+	f, err := os.OpenFile(p.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, os.FileMode(0640))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.WriteString(hash + "\n") // nolint: errcheck
+}
@@ -19,12 +19,28 @@ import (
 	"net/http"
 
 	"github.com/slothking-online/gql/client"
+	"github.com/slothking-online/gql/introspection"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
+var noValidate bool
+
+func noValidateFlag(flags *pflag.FlagSet) {
+	flags.BoolVar(
+		&noValidate,
+		"no-validate",
+		false,
+		"do not validate query against schema before sending it",
+	)
+}
+
 type RawCommandConfig struct {
 	Config
+	// Schema of endpoint used to validate query,
+	// validation is skipped if schema is empty
+	Schema introspection.Schema
 }
 
 // rawCmd represents the raw command
@@ -36,11 +52,20 @@ func NewRawCommand(config RawCommandConfig) *cobra.Command {
 		Short: "Execute raw graphql query",
 		Long: `Executes raw GraphQL query against http GraphQL backend.
 
-Takes exactly one argument, which is graphql query string.`,
-		Run: func(cmd *cobra.Command, args []string) {
+Takes exactly one argument, which is graphql query string. Query is validated against schema of endpoint before it is sent, unless --no-validate is set.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				log.Panicln("command takes exactly one argument")
 			}
+			if !noValidate && config.Schema.QueryType.Name != "" {
+				if errs := config.Schema.Validate(args[0]); errs != nil {
+					if err := printResponse(config.Config, nil, errs); err != nil {
+						return err
+					}
+					config.Exit(ExitError)
+					return nil
+				}
+			}
 			httpHeader := make(http.Header)
 			for k, v := range header {
 				httpHeader.Add(k, v)
@@ -50,20 +75,27 @@ Takes exactly one argument, which is graphql query string.`,
 				Variables:     map[string]interface{}(variables),
 				OperationName: operationName,
 				Header:        httpHeader,
+				Method:        method,
 			}
-			cli := client.New(client.Config{
-				Endpoint: Endpoint,
-			})
-			execute(config.Config, cli, r, nil)
+			cli := newClient(Endpoint, connectTimeout)
+			ctx, cancel := requestContext()
+			defer cancel()
+			return execute(ctx, config.Config, cli, r, nil)
 		},
 	}
 	requiredEndpointFlag(&Endpoint, rawCmd.Flags())
 	formatFlag(rawCmd.Flags())
 	headersFlag(header, rawCmd.Flags())
+	timeoutFlags(rawCmd.Flags())
+	retryFlags(rawCmd.Flags())
+	methodFlag(rawCmd.Flags())
+	persistedFlag(rawCmd.Flags())
+	incrementalFlag(rawCmd.Flags())
+	noValidateFlag(rawCmd.Flags())
 	rawCmd.PersistentFlags().Var(
 		variables,
 		"set",
-		"set grapqhl query variable, can be set multiple times",
+		"set grapqhl query variable, can be set multiple times, value prefixed with @ is a path to file to upload",
 	)
 	rawCmd.PersistentFlags().StringVar(
 		&operationName,
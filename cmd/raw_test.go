@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/slothking-online/gql/introspection"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCaseRawValidate struct {
+	query    string
+	args     []string
+	requests int
+	err      string
+	exit     int
+}
+
+func (tt testCaseRawValidate) test(t *testing.T) {
+	assert := assert.New(t)
+	schema, err := introspection.ParseSDL("type Query { hello(name: String): String }")
+	if !assert.NoError(err) {
+		return
+	}
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"hello":"world"}}`))
+	}))
+	defer srv.Close()
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	exit := 0
+	cmd := NewRawCommand(RawCommandConfig{
+		Config: Config{Out: out, Err: errOut, ExitFunc: func(code int) { exit = code }},
+		Schema: schema,
+	})
+	cmd.SetArgs(append([]string{"--endpoint", srv.URL, tt.query}, tt.args...))
+	assert.NoError(cmd.Execute())
+	assert.Equal(tt.requests, requests)
+	assert.Equal(tt.err, errOut.String())
+	assert.Equal(tt.exit, exit)
+}
+
+func TestRawValidate(t *testing.T) {
+	tests := map[string]testCaseRawValidate{
+		"Valid": {
+			query:    `{ hello(name: "bob") }`,
+			requests: 1,
+		},
+		"Invalid": {
+			query: `{ helo }`,
+			err: `[
+    {
+        "message": "Cannot query field \"helo\" on type \"Query\". Did you mean \"hello\"?",
+        "locations": [
+            {
+                "line": 1,
+                "column": 3
+            }
+        ]
+    }
+]
+`,
+			exit: ExitError,
+		},
+		"NoValidate": {
+			query:    `{ helo }`,
+			args:     []string{"--no-validate"},
+			requests: 1,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.test)
+	}
+}
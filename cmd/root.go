@@ -15,16 +15,55 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 
+	"github.com/slothking-online/gql/client"
 	"github.com/spf13/pflag"
 
 	"github.com/spf13/cobra"
 )
 
+// Exit codes returned by gql
+const (
+	// ExitError is returned on any other error
+	ExitError = 1
+	// ExitBreakingChange is returned by schema diff
+	// if schema changed in a breaking way
+	ExitBreakingChange = 2
+	// ExitHTTPError is returned if endpoint responded
+	// with a body that is not a GraphQL response
+	ExitHTTPError = 3
+	// ExitHTTPClientError is returned on 4xx http status
+	ExitHTTPClientError = 4
+	// ExitHTTPServerError is returned on 5xx http status
+	ExitHTTPServerError = 5
+	// ExitLintError is returned by schema lint if
+	// any of problems has error severity
+	ExitLintError = 6
+	// ExitInterrupted is returned if user interrupted gql
+	ExitInterrupted = 130
+)
+
+const (
+	transportWS   = "ws"
+	transportSSE  = "sse"
+	transportHTTP = "http"
+)
+
+const (
+	incrementalMerge  = "merge"
+	incrementalStream = "stream"
+)
+
 var (
-	format string
+	format      string
+	transport   string
+	incremental string
+	method      string
 )
 
 func headersFlag(header Header, flags *pflag.FlagSet) {
@@ -44,11 +83,42 @@ func formatFlag(flags *pflag.FlagSet) {
 	)
 }
 
+func methodFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&method,
+		"method",
+		"",
+		"http request method, POST if not set, GET sends query in url query string",
+	)
+}
+
+func transportFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&transport,
+		"transport",
+		transportWS,
+		"subscription transport, one of ws, sse or http",
+	)
+}
+
+func incrementalFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&incremental,
+		"incremental",
+		incrementalMerge,
+		"handling of @defer and @stream results, merge prints final result, stream prints each patch",
+	)
+}
+
 // NewRootCommand creates root command a base command for gql
 func NewRootCommand(args []string) *cobra.Command {
 	var Endpoint string
 	header := make(Header)
 	path := Peek(args, &Endpoint, header, nil)
+	schemaTimeout := timeout
+	if introspectionTimeout != 0 {
+		schemaTimeout = introspectionTimeout
+	}
 	rootCmd := &cobra.Command{
 		Use:   "gql",
 		Short: "GraphQL command line client",
@@ -56,13 +126,24 @@ func NewRootCommand(args []string) *cobra.Command {
 	}
 	rootCmd.SetArgs(args)
 	introspectionCmd := NewIntrospectionCommand(IntrospectionCommandConfig{
-		Endpoint: Endpoint,
-		Path:     path,
-		Header:   header,
+		Endpoint:       Endpoint,
+		Path:           path,
+		Header:         header,
+		SchemaFile:     schemaFile,
+		Timeout:        schemaTimeout,
+		ConnectTimeout: connectTimeout,
 	},
 	)
 	rootCmd.AddCommand(introspectionCmd.Command)
-	rootCmd.AddCommand(NewRawCommand(RawCommandConfig{}))
+	rootCmd.AddCommand(NewRawCommand(RawCommandConfig{
+		Schema: introspectionCmd.Schema,
+	}))
+	rootCmd.AddCommand(NewBatchCommand(BatchCommandConfig{}))
+	rootCmd.AddCommand(NewSchemaCommand(SchemaCommandConfig{
+		Endpoint: Endpoint,
+		Header:   header,
+		Schema:   introspectionCmd.Schema,
+	}))
 	rootCmd.AddCommand(NewCompletionCommand(CompletionCommandConfig{}))
 	aliasFieldCommand(rootCmd, introspectionCmd.Query.FieldCommand)
 	aliasFieldCommand(rootCmd, introspectionCmd.Mutation.FieldCommand)
@@ -80,10 +161,33 @@ func aliasFieldCommand(cmd *cobra.Command, fc *FieldCommand) {
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	// Cancel in-flight requests on interrupt
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	baseContext = ctx
 	// Peek flags to find
 	rootCmd := NewRootCommand(os.Args[1:])
 	if err := rootCmd.Execute(); err != nil {
+		if ctx.Err() != nil {
+			// interrupted by user
+			os.Exit(ExitInterrupted)
+		}
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
+	}
+}
+
+// exitCode maps error to gql exit code
+func exitCode(err error) int {
+	var httpErr *client.HTTPError
+	if !errors.As(err, &httpErr) {
+		return ExitError
+	}
+	switch {
+	case httpErr.ClientError():
+		return ExitHTTPClientError
+	case httpErr.ServerError():
+		return ExitHTTPServerError
 	}
+	return ExitHTTPError
 }
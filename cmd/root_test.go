@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/slothking-online/gql/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(ExitError, exitCode(errors.New("error")))
+	assert.Equal(ExitError, exitCode(client.Errors{}))
+	assert.Equal(ExitHTTPClientError, exitCode(&client.HTTPError{StatusCode: 401}))
+	assert.Equal(ExitHTTPServerError, exitCode(&client.HTTPError{StatusCode: 503}))
+	assert.Equal(ExitHTTPError, exitCode(&client.HTTPError{StatusCode: 200}))
+	assert.Equal(ExitHTTPClientError, exitCode(fmt.Errorf("wrapped: %w", &client.HTTPError{StatusCode: 404})))
+}
@@ -0,0 +1,354 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/slothking-online/gql/introspection"
+
+	"github.com/spf13/cobra"
+)
+
+type SchemaCommandConfig struct {
+	Config
+	// Endpoint is a remote GraphQL endpoint
+	Endpoint string
+	// Header is a set of headers sent with introspection query
+	Header Header
+	// Schema is an introspected schema of endpoint
+	Schema introspection.Schema
+}
+
+// NewSchemaCommand creates schema command, a root of commands
+// working on introspected schema
+func NewSchemaCommand(config SchemaCommandConfig) *cobra.Command {
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Inspect GraphQL schema of endpoint",
+		Long:  `Root command for commands inspecting GraphQL schema obtained with introspection.`,
+	}
+	// commands inspecting schema of endpoint need
+	// either endpoint or schema file
+	requireSchema := func(cmd *cobra.Command, args []string) error {
+		if config.Endpoint == "" && schemaFile == "" {
+			return errors.New(`required flag "endpoint" or "schema" not set`)
+		}
+		return nil
+	}
+	endpointFlag(&config.Endpoint, schemaCmd.PersistentFlags())
+	schemaFlag(schemaCmd.PersistentFlags())
+	noCacheFlag(schemaCmd.PersistentFlags())
+	headersFlag(config.Header, schemaCmd.PersistentFlags())
+	timeoutFlags(schemaCmd.PersistentFlags())
+	retryFlags(schemaCmd.PersistentFlags())
+	for _, cmd := range []*cobra.Command{
+		newSchemaPrintCommand(config),
+		newSchemaLintCommand(config),
+		newSchemaSearchCommand(config),
+		newSchemaPathsCommand(config),
+		newSchemaGraphCommand(config),
+		newSchemaDocsCommand(config),
+	} {
+		cmd.PreRunE = requireSchema
+		schemaCmd.AddCommand(cmd)
+	}
+	schemaCmd.AddCommand(newSchemaDiffCommand(config))
+	return schemaCmd
+}
+
+func newSchemaPrintCommand(config SchemaCommandConfig) *cobra.Command {
+	return &cobra.Command{
+		Use:   "print",
+		Short: "Print schema as SDL",
+		Long: `Prints schema as GraphQL schema definition language.
+
+Types and directives are sorted by name, so that output is stable between runs.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, err := fmt.Fprint(config.Output(), config.Schema.SDL())
+			return err
+		},
+	}
+}
+
+func newSchemaSearchCommand(config SchemaCommandConfig) *cobra.Command {
+	var mode string
+	var asJSON bool
+	searchCmd := &cobra.Command{
+		Use:   "search <term>",
+		Short: "Search schema for types, fields and arguments",
+		Long: `Searches every type of schema for types, fields, arguments, input fields and enum values with name or description matching term.
+
+Term is matched as a case insensitive substring, a regular expression or fuzzily, allowing up to two edits. Each match is printed as a resolve path followed by its type, for example:
+
+    query repository issues(first:) -> IssueConnection
+
+Elements that cannot be reached from any of root types, such as input fields, are printed as Type.field.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			results, err := config.Schema.Search(args[0], introspection.SearchMode(mode))
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				if results == nil {
+					results = []introspection.SearchResult{}
+				}
+				b, err := json.MarshalIndent(results, "", "    ")
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprintln(config.Output(), string(b))
+				return err
+			}
+			if len(results) == 0 {
+				fmt.Fprintln(config.Error(), "No matches")
+				return nil
+			}
+			for _, r := range results {
+				if _, err := fmt.Fprintln(config.Output(), r.String()); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	searchCmd.Flags().StringVar(&mode, "mode", string(introspection.SearchSubstring), "match mode, one of substring, regex or fuzzy")
+	searchCmd.Flags().BoolVar(&asJSON, "json", false, "print matches as JSON")
+	return searchCmd
+}
+
+// pathCommand formats resolve path as gql command, required
+// arguments are set to placeholders with argument type. Steps
+// after field narrowed to possible type are added with --fields
+// as inline fragment, ending with __typename of composite target
+func pathCommand(p introspection.ResolvePath, composite bool) string {
+	parts := []string{"gql", p.Operation}
+	for i, s := range p.Steps {
+		parts = append(parts, s.Field)
+		for _, a := range s.Required {
+			parts = append(parts, fmt.Sprintf("--arg-%s='<%s>'", a.Name, a.Type.GoString()))
+		}
+		if s.On != "" {
+			parts = append(parts, fmt.Sprintf("--fields '... on %s %s'", s.On, pathSelection(p.Steps[i+1:], composite)))
+			break
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// pathSelection formats steps as nested selection set
+func pathSelection(steps []introspection.PathStep, composite bool) string {
+	if len(steps) == 0 {
+		return "{ __typename }"
+	}
+	s := steps[0]
+	field := s.Field
+	if len(s.Required) != 0 {
+		args := make([]string, 0, len(s.Required))
+		for _, a := range s.Required {
+			args = append(args, fmt.Sprintf("%s: <%s>", a.Name, a.Type.GoString()))
+		}
+		field += "(" + strings.Join(args, ", ") + ")"
+	}
+	switch {
+	case s.On != "":
+		field += fmt.Sprintf(" { ... on %s %s }", s.On, pathSelection(steps[1:], composite))
+	case len(steps) > 1 || composite:
+		field += " " + pathSelection(steps[1:], composite)
+	}
+	return "{ " + field + " }"
+}
+
+func newSchemaPathsCommand(config SchemaCommandConfig) *cobra.Command {
+	var limit, maxDepth int
+	pathsCmd := &cobra.Command{
+		Use:   "paths <type>",
+		Short: "Find resolve paths to type",
+		Long: `Finds shortest resolve paths from root operations to fields returning type.
+
+Each path is printed as gql command, with required arguments set to placeholders of argument type, for example:
+
+    gql query repository --arg-owner='<String!>' --arg-name='<String!>' issues
+
+Fields returning unions or interfaces are followed through their members and implementations, the rest of such path is selected with inline fragment in --fields, for example:
+
+    gql query search --arg-text='<String!>' --fields '... on Issue { labels { __typename } }'
+
+Paths never visit the same type twice and paths longer than --max-depth fields are not searched.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if limit < 1 {
+				return errors.New("limit must be greater than 0")
+			}
+			if maxDepth < 1 {
+				return errors.New("max-depth must be greater than 0")
+			}
+			t := (introspection.Type{Name: args[0]}).Deref(config.Schema.Types)
+			if t.Name == "" {
+				return fmt.Errorf("type %s not found", args[0])
+			}
+			composite := t.Object() || t.Interface() || t.Union()
+			paths := config.Schema.PathsTo(args[0], limit, maxDepth)
+			if len(paths) == 0 {
+				fmt.Fprintln(config.Error(), "No paths")
+				return nil
+			}
+			for _, p := range paths {
+				if _, err := fmt.Fprintln(config.Output(), pathCommand(p, composite)); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	pathsCmd.Flags().IntVar(&limit, "limit", 10, "maximum number of paths printed")
+	pathsCmd.Flags().IntVar(&maxDepth, "max-depth", 10, "maximum number of fields in path")
+	return pathsCmd
+}
+
+func newSchemaGraphCommand(config SchemaCommandConfig) *cobra.Command {
+	var output string
+	var opts introspection.GraphOptions
+	graphCmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Export type graph as DOT or Mermaid",
+		Long: `Prints graph of schema types in Graphviz DOT language or as Mermaid flowchart.
+
+Objects, interfaces and unions are nodes and fields returning them are edges labeled with field name. Implemented interfaces and union members are drawn with dashed and dotted edges. With --root, graph is limited to types reachable from root type, optionally at most --depth edges away from it.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			g, err := config.Schema.Graph(opts)
+			if err != nil {
+				return err
+			}
+			switch output {
+			case "dot":
+				_, err = fmt.Fprint(config.Output(), g.DOT())
+			case "mermaid":
+				_, err = fmt.Fprint(config.Output(), g.Mermaid())
+			default:
+				err = fmt.Errorf("unknown output %s", output)
+			}
+			return err
+		},
+	}
+	graphCmd.Flags().StringVar(&output, "output", "dot", "graph output, one of dot or mermaid")
+	graphCmd.Flags().StringVar(&opts.Root, "root", "", "include only types reachable from root type")
+	graphCmd.Flags().IntVar(&opts.Depth, "depth", 0, "include only types at most depth edges away from root type, 0 means no limit")
+	graphCmd.Flags().BoolVar(&opts.ExcludeIntrospection, "exclude-introspection-types", false, "exclude introspection types such as __Type from graph")
+	return graphCmd
+}
+
+func newSchemaDocsCommand(config SchemaCommandConfig) *cobra.Command {
+	var out, output string
+	docsCmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Generate reference documentation of schema",
+		Long: `Renders reference documentation of schema into directory.
+
+Markdown output writes README.md, an index of types and directives, and a page for each of types named after type, such as User.md. HTML output writes a single index.html. Both outputs have stable anchors named after schema elements, so that documentation of fields and arguments can be linked to.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var pages map[string]string
+			switch output {
+			case "markdown":
+				pages = config.Schema.MarkdownDocs()
+			case "html":
+				pages = map[string]string{"index.html": config.Schema.HTMLDocs()}
+			default:
+				return fmt.Errorf("unknown output %s", output)
+			}
+			if err := os.MkdirAll(out, 0755); err != nil {
+				return err
+			}
+			for name, page := range pages {
+				if err := ioutil.WriteFile(filepath.Join(out, name), []byte(page), 0644); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	docsCmd.Flags().StringVar(&out, "out", "docs", "directory to which documentation is written")
+	docsCmd.Flags().StringVar(&output, "output", "markdown", "documentation output, one of markdown or html")
+	return docsCmd
+}
+
+// loadSchema introspects endpoint if src is an http url,
+// otherwise reads schema from file
+func loadSchema(src string, header Header) (introspection.Schema, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		return readSchemaFile(src)
+	}
+	httpHeader := make(http.Header)
+	for k, v := range header {
+		httpHeader.Add(k, v)
+	}
+	ctx, cancel := withTimeout(timeout)
+	defer cancel()
+	return introspection.GetSchemaContext(ctx, newClient(src, connectTimeout), httpHeader)
+}
+
+func writeChanges(out io.Writer, changes introspection.Changes, asJSON bool) error {
+	if asJSON {
+		if changes == nil {
+			changes = introspection.Changes{}
+		}
+		b, err := json.MarshalIndent(changes, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(b))
+		return err
+	}
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(out, "No changes")
+		return err
+	}
+	for _, c := range changes {
+		if _, err := fmt.Fprintf(out, "%-9s  %s\n", strings.ToUpper(string(c.Criticality)), c.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newSchemaDiffCommand(config SchemaCommandConfig) *cobra.Command {
+	var asJSON bool
+	diffCmd := &cobra.Command{
+		Use:   "diff <old> <new>",
+		Short: "Compare two schemas",
+		Long: `Compares two schemas, listing changes classified as breaking, dangerous or safe.
+
+Each of schemas is either an http(s) url of endpoint, which is introspected, or a file with schema definition language or introspection JSON. Breaking changes make existing queries invalid, dangerous changes may change results of existing queries. Exits with code 2 if any of changes is breaking.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := loadSchema(args[0], config.Header)
+			if err != nil {
+				return err
+			}
+			n, err := loadSchema(args[1], config.Header)
+			if err != nil {
+				return err
+			}
+			changes := introspection.Diff(o, n)
+			if err := writeChanges(config.Output(), changes, asJSON); err != nil {
+				return err
+			}
+			if changes.Breaking() {
+				config.Exit(ExitBreakingChange)
+			}
+			return nil
+		},
+	}
+	diffCmd.Flags().BoolVar(&asJSON, "json", false, "print changes as JSON")
+	return diffCmd
+}
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/slothking-online/gql/introspection"
+
+	"github.com/spf13/cobra"
+)
+
+func readLintConfig(fn string) (introspection.LintConfig, error) {
+	var config introspection.LintConfig
+	if fn == "" {
+		return config, nil
+	}
+	b, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return config, err
+	}
+	if err := json.Unmarshal(b, &config); err != nil {
+		return config, fmt.Errorf("%s: %v", fn, err)
+	}
+	return config, nil
+}
+
+func writeProblemsText(out io.Writer, problems introspection.LintProblems) error {
+	if len(problems) == 0 {
+		_, err := fmt.Fprintln(out, "No problems")
+		return err
+	}
+	for _, p := range problems {
+		if _, err := fmt.Fprintf(out, "%-7s  %s  [%s]\n", strings.ToUpper(string(p.Severity)), p.Message, p.Rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeProblemsJSON(out io.Writer, problems introspection.LintProblems) error {
+	if problems == nil {
+		problems = introspection.LintProblems{}
+	}
+	b, err := json.MarshalIndent(problems, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, string(b))
+	return err
+}
+
+// sarif types cover subset of SARIF 2.1.0 used by lint report
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+func sarifLevel(s introspection.Severity) string {
+	switch s {
+	case introspection.SeverityError:
+		return "error"
+	case introspection.SeverityWarning:
+		return "warning"
+	case introspection.SeverityOff:
+		return "none"
+	}
+	return "note"
+}
+
+// writeProblemsSARIF writes problems as SARIF log, if uri is
+// not empty, it is used as a location of each of problems
+func writeProblemsSARIF(out io.Writer, problems introspection.LintProblems, uri string) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "gql",
+			InformationURI: "https://github.com/slothking-online/gql",
+		}},
+		Results: []sarifResult{},
+	}
+	for _, r := range introspection.LintRules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:                   r.Name,
+			ShortDescription:     sarifMessage{r.Description},
+			DefaultConfiguration: sarifConfiguration{sarifLevel(r.Severity)},
+		})
+	}
+	for _, p := range problems {
+		loc := sarifLocation{
+			LogicalLocations: []sarifLogicalLocation{{p.Path}},
+		}
+		if uri != "" {
+			loc.PhysicalLocation = &sarifPhysicalLocation{sarifArtifactLocation{uri}}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    p.Rule,
+			Level:     sarifLevel(p.Severity),
+			Message:   sarifMessage{p.Message},
+			Locations: []sarifLocation{loc},
+		})
+	}
+	b, err := json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, string(b))
+	return err
+}
+
+func newSchemaLintCommand(config SchemaCommandConfig) *cobra.Command {
+	var lintConfig, output string
+	lintCmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check schema against lint rules",
+		Long: `Runs lint rules over schema and reports problems found.
+
+Rules and their severity are selected with JSON config file, for example:
+
+    {
+        "rules": {
+            "description-required": "error",
+            "input-suffix": "off"
+        }
+    }
+
+Severity is one of error, warning, info or off. Rules missing from config are run with their default severity. Report is printed as text, JSON or SARIF. Exits with code 6 if any of problems has error severity.` + lintRulesHelp(),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			lc, err := readLintConfig(lintConfig)
+			if err != nil {
+				return err
+			}
+			problems, err := introspection.Lint(config.Schema, lc)
+			if err != nil {
+				return err
+			}
+			switch output {
+			case "text":
+				err = writeProblemsText(config.Output(), problems)
+			case "json":
+				err = writeProblemsJSON(config.Output(), problems)
+			case "sarif":
+				err = writeProblemsSARIF(config.Output(), problems, schemaFile)
+			default:
+				err = fmt.Errorf("unknown output %s", output)
+			}
+			if err != nil {
+				return err
+			}
+			if problems.Errors() {
+				config.Exit(ExitLintError)
+			}
+			return nil
+		},
+	}
+	lintCmd.Flags().StringVar(&lintConfig, "config", "", "path to lint config file")
+	lintCmd.Flags().StringVar(&output, "output", "text", "report output, one of text, json or sarif")
+	return lintCmd
+}
+
+// lintRulesHelp lists known lint rules for command help
+func lintRulesHelp() string {
+	var sb strings.Builder
+	sb.WriteString("\n\nRules and their default severity:\n")
+	for _, r := range introspection.LintRules {
+		fmt.Fprintf(&sb, "  %-22s %-8s %s\n", r.Name, r.Severity, r.Description)
+	}
+	return sb.String()
+}
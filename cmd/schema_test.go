@@ -0,0 +1,441 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/slothking-online/gql/introspection"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaPrintCommand(t *testing.T) {
+	assert := assert.New(t)
+	out := &bytes.Buffer{}
+	schema := introspection.Schema{
+		QueryType: introspection.Type{Name: "Query"},
+		Types: []introspection.Type{
+			{Kind: "OBJECT", Name: "Query", Fields: []introspection.Field{
+				{Name: "a", Type: introspection.Type{Kind: "SCALAR", Name: "String"}},
+			}},
+		},
+	}
+	cmd := NewSchemaCommand(SchemaCommandConfig{
+		Config: Config{Out: out},
+		Header: Header{},
+		Schema: schema,
+	})
+	cmd.SetArgs([]string{"print", "--endpoint", "http://example.com"})
+	assert.NoError(cmd.Execute())
+	assert.Equal("type Query {\n  a: String\n}\n", out.String())
+}
+
+func writeTempSchema(t *testing.T, sdl string) string {
+	f, err := ioutil.TempFile("", "schema*.graphql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(sdl); err != nil {
+		t.Fatal(err)
+	}
+	return f.Name()
+}
+
+type testCaseSchemaDiffCommand struct {
+	old, new string
+	json     bool
+	out      string
+	exit     int
+}
+
+func (tt testCaseSchemaDiffCommand) test(t *testing.T) {
+	assert := assert.New(t)
+	o := writeTempSchema(t, tt.old)
+	defer os.Remove(o)
+	n := writeTempSchema(t, tt.new)
+	defer os.Remove(n)
+	out := &bytes.Buffer{}
+	exit := 0
+	cmd := NewSchemaCommand(SchemaCommandConfig{
+		Config: Config{Out: out, ExitFunc: func(code int) { exit = code }},
+		Header: Header{},
+	})
+	args := []string{"diff", o, n}
+	if tt.json {
+		args = append(args, "--json")
+	}
+	cmd.SetArgs(args)
+	assert.NoError(cmd.Execute())
+	assert.Equal(tt.out, out.String())
+	assert.Equal(tt.exit, exit)
+}
+
+func TestSchemaDiffCommand(t *testing.T) {
+	tests := map[string]testCaseSchemaDiffCommand{
+		"NoChanges": {
+			old: "type Query { a: String }",
+			new: "type Query { a: String }",
+			out: "No changes\n",
+		},
+		"Safe": {
+			old: "type Query { a: String }",
+			new: "type Query { a: String b: Int }",
+			out: "SAFE       Field Query.b was added\n",
+		},
+		"Breaking": {
+			old:  "type Query { a: String b: Int }",
+			new:  "type Query { a: String }",
+			out:  "BREAKING   Field Query.b was removed\n",
+			exit: ExitBreakingChange,
+		},
+		"JSON": {
+			old:  "type Query { a: String b: Int }",
+			new:  "type Query { a: String }",
+			json: true,
+			out: `[
+    {
+        "criticality": "breaking",
+        "type": "FIELD_REMOVED",
+        "path": "Query.b",
+        "message": "Field Query.b was removed"
+    }
+]
+`,
+			exit: ExitBreakingChange,
+		},
+		"EmptyJSON": {
+			old:  "type Query { a: String }",
+			new:  "type Query { a: String }",
+			json: true,
+			out:  "[]\n",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.test)
+	}
+}
+
+type testCaseSchemaLintCommand struct {
+	sdl    string
+	config string
+	output string
+	out    string
+	exit   int
+	err    bool
+}
+
+func (tt testCaseSchemaLintCommand) test(t *testing.T) {
+	assert := assert.New(t)
+	fn := writeTempSchema(t, tt.sdl)
+	defer os.Remove(fn)
+	args := []string{"lint", "--schema", fn}
+	if tt.config != "" {
+		cfg := writeTempSchema(t, tt.config)
+		defer os.Remove(cfg)
+		args = append(args, "--config", cfg)
+	}
+	if tt.output != "" {
+		args = append(args, "--output", tt.output)
+	}
+	schema, err := readSchemaFile(fn)
+	if !assert.NoError(err) {
+		return
+	}
+	out := &bytes.Buffer{}
+	exit := 0
+	cmd := NewSchemaCommand(SchemaCommandConfig{
+		Config: Config{Out: out, Err: &bytes.Buffer{}, ExitFunc: func(code int) { exit = code }},
+		Header: Header{},
+		Schema: schema,
+	})
+	cmd.SilenceUsage = true
+	cmd.SetArgs(args)
+	if tt.err {
+		assert.Error(cmd.Execute())
+		return
+	}
+	assert.NoError(cmd.Execute())
+	assert.Equal(strings.Replace(tt.out, "$FILE", fn, -1), out.String())
+	assert.Equal(tt.exit, exit)
+}
+
+func TestSchemaLintCommand(t *testing.T) {
+	const offConfig = `{"rules": {"description-required": "off", "input-suffix": "off"}}`
+	tests := map[string]testCaseSchemaLintCommand{
+		"NoProblems": {
+			sdl:    "type Query { a: String }",
+			config: offConfig,
+			out:    "No problems\n",
+		},
+		"Text": {
+			sdl:  `"q" type Query { "a" a(f: Filter): E } "e" enum E { "v" lower } "f" input Filter { "a" a: Int }`,
+			out:  "ERROR    Enum value E.lower is not in UPPER_CASE  [enum-upper-case]\nWARNING  Input type Filter does not end with Input  [input-suffix]\n",
+			exit: ExitLintError,
+		},
+		"Warnings": {
+			sdl:    "type Query { a: String @deprecated }",
+			config: `{"rules": {"description-required": "off"}}`,
+			out:    "WARNING  Field Query.a is deprecated without a reason  [deprecation-reason]\n",
+		},
+		"JSON": {
+			sdl:    "type Query { a_b: String }",
+			config: offConfig,
+			output: "json",
+			out: `[
+    {
+        "rule": "field-camel-case",
+        "severity": "error",
+        "path": "Query.a_b",
+        "message": "Field Query.a_b is not in camelCase"
+    }
+]
+`,
+			exit: ExitLintError,
+		},
+		"SARIF": {
+			sdl:    "type Query { a_b: String }",
+			config: `{"rules": {"description-required": "off", "input-suffix": "off", "deprecation-reason": "off", "enum-upper-case": "off", "relay-connection": "off", "field-camel-case": "info"}}`,
+			output: "sarif",
+			out: `{
+    "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+    "version": "2.1.0",
+    "runs": [
+        {
+            "tool": {
+                "driver": {
+                    "name": "gql",
+                    "informationUri": "https://github.com/slothking-online/gql",
+                    "rules": [
+                        {
+                            "id": "description-required",
+                            "shortDescription": {
+                                "text": "Types, fields, input fields and enum values have descriptions"
+                            },
+                            "defaultConfiguration": {
+                                "level": "warning"
+                            }
+                        },
+                        {
+                            "id": "deprecation-reason",
+                            "shortDescription": {
+                                "text": "Deprecated fields, arguments and enum values have a deprecation reason"
+                            },
+                            "defaultConfiguration": {
+                                "level": "warning"
+                            }
+                        },
+                        {
+                            "id": "field-camel-case",
+                            "shortDescription": {
+                                "text": "Fields, input fields and arguments are named in camelCase"
+                            },
+                            "defaultConfiguration": {
+                                "level": "error"
+                            }
+                        },
+                        {
+                            "id": "enum-upper-case",
+                            "shortDescription": {
+                                "text": "Enum values are named in UPPER_CASE"
+                            },
+                            "defaultConfiguration": {
+                                "level": "error"
+                            }
+                        },
+                        {
+                            "id": "input-suffix",
+                            "shortDescription": {
+                                "text": "Input object type names end with Input"
+                            },
+                            "defaultConfiguration": {
+                                "level": "warning"
+                            }
+                        },
+                        {
+                            "id": "relay-connection",
+                            "shortDescription": {
+                                "text": "Connection types follow Relay cursor connections specification"
+                            },
+                            "defaultConfiguration": {
+                                "level": "error"
+                            }
+                        }
+                    ]
+                }
+            },
+            "results": [
+                {
+                    "ruleId": "field-camel-case",
+                    "level": "note",
+                    "message": {
+                        "text": "Field Query.a_b is not in camelCase"
+                    },
+                    "locations": [
+                        {
+                            "physicalLocation": {
+                                "artifactLocation": {
+                                    "uri": "$FILE"
+                                }
+                            },
+                            "logicalLocations": [
+                                {
+                                    "fullyQualifiedName": "Query.a_b"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ]
+        }
+    ]
+}
+`,
+		},
+		"UnknownRule": {
+			sdl:    "type Query { a: String }",
+			config: `{"rules": {"missing": "error"}}`,
+			err:    true,
+		},
+		"UnknownOutput": {
+			sdl:    "type Query { a: String }",
+			output: "xml",
+			err:    true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.test)
+	}
+}
+
+func TestSchemaSearchCommand(t *testing.T) {
+	assert := assert.New(t)
+	schema, err := introspection.ParseSDL("type Query { user(id: ID!): User } type User { name: String } input UserInput { name: String }")
+	if !assert.NoError(err) {
+		return
+	}
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd := NewSchemaCommand(SchemaCommandConfig{
+		Config: Config{Out: out, Err: errOut},
+		Header: Header{},
+		Schema: schema,
+	})
+	cmd.SetArgs([]string{"search", "--endpoint", "http://example.com", "name"})
+	assert.NoError(cmd.Execute())
+	assert.Equal("query user name -> String\nUserInput.name -> String\n", out.String())
+	out.Reset()
+	cmd.SetArgs([]string{"search", "--endpoint", "http://example.com", "--mode", "regex", "^ID$"})
+	assert.NoError(cmd.Execute())
+	assert.Equal("", out.String())
+	assert.Equal("No matches\n", errOut.String())
+}
+
+func TestSchemaPathsCommand(t *testing.T) {
+	assert := assert.New(t)
+	schema, err := introspection.ParseSDL(`type Query { repository(name: String!, first: Int = 1): Repository viewer: User }
+type User { repository: Repository }
+type Repository { owner(active: Boolean!): User }`)
+	if !assert.NoError(err) {
+		return
+	}
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd := NewSchemaCommand(SchemaCommandConfig{
+		Config: Config{Out: out, Err: errOut},
+		Header: Header{},
+		Schema: schema,
+	})
+	cmd.SilenceErrors, cmd.SilenceUsage = true, true
+	cmd.SetArgs([]string{"paths", "--endpoint", "http://example.com", "User"})
+	assert.NoError(cmd.Execute())
+	assert.Equal(`gql query viewer
+gql query repository --arg-name='<String!>' owner --arg-active='<Boolean!>'
+`, out.String())
+	out.Reset()
+	cmd.SetArgs([]string{"paths", "--endpoint", "http://example.com", "--limit", "1", "Repository"})
+	assert.NoError(cmd.Execute())
+	assert.Equal("gql query repository --arg-name='<String!>'\n", out.String())
+	cmd.SetArgs([]string{"paths", "--endpoint", "http://example.com", "Missing"})
+	assert.EqualError(cmd.Execute(), "type Missing not found")
+	out.Reset()
+	cmd.SetArgs([]string{"paths", "--endpoint", "http://example.com", "--limit", "10", "--max-depth", "1", "User"})
+	assert.NoError(cmd.Execute())
+	assert.Equal("gql query viewer\n", out.String())
+	cmd.SetArgs([]string{"paths", "--endpoint", "http://example.com", "--limit", "0", "User"})
+	assert.EqualError(cmd.Execute(), "limit must be greater than 0")
+	cmd.SetArgs([]string{"paths", "--endpoint", "http://example.com", "--limit", "1", "--max-depth", "-1", "User"})
+	assert.EqualError(cmd.Execute(), "max-depth must be greater than 0")
+}
+
+func TestSchemaPathsCommandUnion(t *testing.T) {
+	assert := assert.New(t)
+	schema, err := introspection.ParseSDL(`type Query { search(text: String!): [Result] }
+union Result = Issue | Label
+type Issue { labels(first: Int!): [Label] }
+type Label { name: String }`)
+	if !assert.NoError(err) {
+		return
+	}
+	out := &bytes.Buffer{}
+	cmd := NewSchemaCommand(SchemaCommandConfig{
+		Config: Config{Out: out},
+		Header: Header{},
+		Schema: schema,
+	})
+	cmd.SilenceErrors, cmd.SilenceUsage = true, true
+	cmd.SetArgs([]string{"paths", "--endpoint", "http://example.com", "Label"})
+	assert.NoError(cmd.Execute())
+	assert.Equal(`gql query search --arg-text='<String!>' --fields '... on Label { __typename }'
+gql query search --arg-text='<String!>' --fields '... on Issue { labels(first: <Int!>) { __typename } }'
+`, out.String())
+}
+
+func TestSchemaGraphCommand(t *testing.T) {
+	assert := assert.New(t)
+	schema, err := introspection.ParseSDL("type Query { user: User } type User { name: String }")
+	if !assert.NoError(err) {
+		return
+	}
+	out := &bytes.Buffer{}
+	cmd := NewSchemaCommand(SchemaCommandConfig{
+		Config: Config{Out: out},
+		Header: Header{},
+		Schema: schema,
+	})
+	cmd.SilenceErrors, cmd.SilenceUsage = true, true
+	cmd.SetArgs([]string{"graph", "--endpoint", "http://example.com", "--output", "mermaid", "--root", "Query"})
+	assert.NoError(cmd.Execute())
+	assert.Equal("flowchart LR\n  n_Query[\"Query\"]\n  n_User[\"User\"]\n  n_Query -->|user| n_User\n", out.String())
+	cmd.SetArgs([]string{"graph", "--endpoint", "http://example.com", "--output", "svg"})
+	assert.EqualError(cmd.Execute(), "unknown output svg")
+}
+
+func TestSchemaDocsCommand(t *testing.T) {
+	assert := assert.New(t)
+	schema, err := introspection.ParseSDL("type Query { user: User } type User { name: String }")
+	if !assert.NoError(err) {
+		return
+	}
+	dir, err := ioutil.TempDir("", "docs")
+	if !assert.NoError(err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	cmd := NewSchemaCommand(SchemaCommandConfig{
+		Header: Header{},
+		Schema: schema,
+	})
+	cmd.SetArgs([]string{"docs", "--endpoint", "http://example.com", "--out", dir})
+	assert.NoError(cmd.Execute())
+	for _, name := range []string{"README.md", "Query.md", "User.md"} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		assert.NoError(err)
+	}
+	cmd.SetArgs([]string{"docs", "--endpoint", "http://example.com", "--out", dir, "--output", "html"})
+	assert.NoError(cmd.Execute())
+	b, err := ioutil.ReadFile(filepath.Join(dir, "index.html"))
+	assert.NoError(err)
+	assert.Contains(string(b), `<section id="User">`)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"strings"
 
+	"github.com/slothking-online/gql/client"
 	"github.com/wolfeidau/unflatten"
 )
 
@@ -27,13 +28,25 @@ func (v Variables) Set(val string) error {
 	if len(ss) != 2 {
 		return errors.New("must be in {key}={value} format")
 	}
+	v[ss[0]] = variableValue(ss[1])
+	return nil
+}
+
+// variableValue parses variable value, which is either
+// a file to upload if prefixed with @, JSON or a string.
+// Leading @@ escapes a string starting with @
+func variableValue(val string) interface{} {
+	switch {
+	case strings.HasPrefix(val, "@@"):
+		return val[1:]
+	case strings.HasPrefix(val, "@"):
+		return client.FileUpload(val[1:])
+	}
 	var i interface{}
-	err := json.Unmarshal([]byte(ss[1]), &i)
-	if err != nil {
-		i = ss[1]
+	if err := json.Unmarshal([]byte(val), &i); err != nil {
+		i = val
 	}
-	v[ss[0]] = i
-	return nil
+	return i
 }
 
 func (v Variables) Unflatten() map[string]interface{} {
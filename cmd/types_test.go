@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/slothking-online/gql/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariablesSet(t *testing.T) {
+	assert := assert.New(t)
+	v := Variables{}
+	assert.NoError(v.Set("a=1"))
+	assert.NoError(v.Set("b=text"))
+	assert.NoError(v.Set("c=@@handle"))
+	assert.NoError(v.Set("d=@./dir/file.png"))
+	assert.Error(v.Set("e"))
+	assert.Equal(float64(1), v["a"])
+	assert.Equal("text", v["b"])
+	assert.Equal("@handle", v["c"])
+	if assert.IsType(&client.Upload{}, v["d"]) {
+		assert.Equal("file.png", v["d"].(*client.Upload).Filename)
+	}
+}
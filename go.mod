@@ -4,6 +4,7 @@ require (
 	github.com/aexol/test_util v0.0.0-20190105143726-b9af6d8a88ab
 	github.com/agnivade/levenshtein v1.0.1
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/gorilla/websocket v1.5.3
 	github.com/graphql-go/graphql v0.7.7
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
 	github.com/kballard/go-shellquote v0.0.0-20180428030007-95032a82bc51
@@ -14,4 +15,5 @@ require (
 	github.com/stretchr/objx v0.1.1 // indirect
 	github.com/stretchr/testify v1.2.2
 	github.com/wolfeidau/unflatten v1.0.1
+	gopkg.in/yaml.v2 v2.4.0
 )
@@ -0,0 +1,183 @@
+package introspection
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/slothking-online/gql/client"
+)
+
+// capabilities are introspection features added by newer
+// revisions of GraphQL specification, which are not supported
+// by every server
+type capabilities struct {
+	schemaDescription     bool
+	specifiedByURL        bool
+	isOneOf               bool
+	isRepeatable          bool
+	inputValueDeprecation bool
+}
+
+const capabilitiesQuery = `query {
+    schema: __type(name: "__Schema") { fields { name } }
+    type: __type(name: "__Type") { fields { name } }
+    directive: __type(name: "__Directive") { fields { name } }
+    inputValue: __type(name: "__InputValue") { fields { name } }
+}`
+
+type introspectionTypeFields struct {
+	Fields []struct {
+		Name string `json:"name"`
+	} `json:"fields"`
+}
+
+func (t *introspectionTypeFields) has(name string) bool {
+	if t == nil {
+		return false
+	}
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// probeCapabilities checks which of newer introspection
+// features are supported by remote endpoint
+func probeCapabilities(ctx context.Context, cli *client.Client, header http.Header) (capabilities, error) {
+	r := client.Raw{
+		Query:  capabilitiesQuery,
+		Header: header,
+	}
+	out := struct {
+		Schema     *introspectionTypeFields `json:"schema"`
+		Type       *introspectionTypeFields `json:"type"`
+		Directive  *introspectionTypeFields `json:"directive"`
+		InputValue *introspectionTypeFields `json:"inputValue"`
+	}{}
+	if _, err := cli.RawContext(ctx, r, &out); err != nil {
+		if _, ok := err.(client.Errors); ok {
+			// assume no support for newer features
+			return capabilities{}, nil
+		}
+		return capabilities{}, err
+	}
+	return capabilities{
+		schemaDescription:     out.Schema.has("description"),
+		specifiedByURL:        out.Type.has("specifiedByURL"),
+		isOneOf:               out.Type.has("isOneOf"),
+		isRepeatable:          out.Directive.has("isRepeatable"),
+		inputValueDeprecation: out.InputValue.has("isDeprecated"),
+	}, nil
+}
+
+func line(ok bool, s string) string {
+	if !ok {
+		return ""
+	}
+	return s
+}
+
+// schemaQuery returns introspection query asking for
+// all features supported by remote endpoint
+func (c capabilities) schemaQuery() string {
+	includeDeprecated := line(c.inputValueDeprecation, "(includeDeprecated: true)")
+	fullType := fmt.Sprintf(`fragment FullType on __Type {
+    kind
+    name
+    description
+%s    fields(includeDeprecated: true) {
+        name
+        description
+        args%s {
+        ...InputValue
+        }
+        type {
+        ...TypeRef
+        }
+        isDeprecated
+        deprecationReason
+    }
+    inputFields%s {
+        ...InputValue
+    }
+    interfaces {
+        ...TypeRef
+    }
+    enumValues(includeDeprecated: true) {
+        name
+        description
+        isDeprecated
+        deprecationReason
+    }
+    possibleTypes {
+        ...TypeRef
+    }
+%s}`,
+		line(c.specifiedByURL, "    specifiedByURL\n"),
+		includeDeprecated,
+		includeDeprecated,
+		line(c.isOneOf, "    isOneOf\n"),
+	)
+	inputValue := fmt.Sprintf(`fragment InputValue on __InputValue {
+    name
+    description
+    type { ...TypeRef }
+    defaultValue
+%s}`,
+		line(c.inputValueDeprecation, "    isDeprecated\n    deprecationReason\n"),
+	)
+	query := fmt.Sprintf(`query IntrospectionQuery {
+    __schema {
+%s        queryType { name }
+        mutationType { name }
+        subscriptionType { name }
+        types {
+        ...FullType
+        }
+        directives {
+        name
+        description
+        locations
+%s        args%s {
+            ...InputValue
+        }
+        }
+    }
+}`,
+		line(c.schemaDescription, "        description\n"),
+		line(c.isRepeatable, "        isRepeatable\n"),
+		includeDeprecated,
+	)
+	return query + "\n" + fullType + "\n" + inputValue + "\n" + fragmentTypeRef
+}
+
+// GetSchema runs introspection query on remote endpoint returning
+// schema. Unlike GetSchemaTypes, it first checks which introspection
+// features are supported by endpoint, asking for everything that
+// is available, such as specifiedByURL, isRepeatable, isOneOf and
+// deprecation of arguments.
+func GetSchema(cli *client.Client, header http.Header) (Schema, error) {
+	return GetSchemaContext(context.Background(), cli, header)
+}
+
+// GetSchemaContext is GetSchema cancelling request when ctx is done
+func GetSchemaContext(ctx context.Context, cli *client.Client, header http.Header) (Schema, error) {
+	c, err := probeCapabilities(ctx, cli, header)
+	if err != nil {
+		return Schema{}, err
+	}
+	r := client.Raw{
+		Query:  c.schemaQuery(),
+		Header: header,
+	}
+	out := struct {
+		Schema Schema `json:"__schema"`
+	}{}
+	if _, err := cli.RawContext(ctx, r, &out); err != nil {
+		return Schema{}, err
+	}
+	return out.Schema, nil
+}
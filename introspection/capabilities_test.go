@@ -0,0 +1,84 @@
+package introspection
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/slothking-online/gql/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapabilitiesSchemaQuery(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(schemaInfoQuery, capabilities{}.schemaQuery())
+	q := capabilities{
+		schemaDescription:     true,
+		specifiedByURL:        true,
+		isOneOf:               true,
+		isRepeatable:          true,
+		inputValueDeprecation: true,
+	}.schemaQuery()
+	_, err := parser.Parse(parser.ParseParams{Source: q})
+	assert.NoError(err)
+	for _, s := range []string{"specifiedByURL", "isOneOf", "isRepeatable", "args(includeDeprecated: true)", "inputFields(includeDeprecated: true)"} {
+		assert.Contains(q, s)
+	}
+}
+
+func TestGetSchema(t *testing.T) {
+	assert := assert.New(t)
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req client.Raw
+		b, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(b, &req) // nolint: errcheck
+		queries = append(queries, req.Query)
+		if req.Query == capabilitiesQuery {
+			fmt.Fprint(w, `{"data":{
+				"schema":{"fields":[{"name":"types"}]},
+				"type":{"fields":[{"name":"specifiedByURL"}]},
+				"directive":{"fields":[{"name":"isRepeatable"}]},
+				"inputValue":{"fields":[{"name":"name"}]}
+			}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"__schema":{
+			"queryType":{"name":"Query"},
+			"types":[
+				{"kind":"SCALAR","name":"Date","specifiedByURL":"https://example.com"},
+				{"kind":"ENUM","name":"Color","enumValues":[{"name":"RED"},{"name":"BLUE","isDeprecated":true,"deprecationReason":"no"}]},
+				{"kind":"INPUT_OBJECT","name":"Filter","inputFields":[{"name":"color","type":{"kind":"ENUM","name":"Color"},"defaultValue":"RED"}]}
+			],
+			"directives":[{"name":"tag","locations":["FIELD"],"isRepeatable":true,"args":[]}]
+		}}}`)
+	}))
+	defer srv.Close()
+	schema, err := GetSchema(client.New(client.Config{Endpoint: srv.URL}), nil)
+	assert.NoError(err)
+	assert.Len(queries, 2)
+	assert.True(strings.Contains(queries[1], "specifiedByURL"))
+	assert.True(strings.Contains(queries[1], "isRepeatable"))
+	assert.False(strings.Contains(queries[1], "isOneOf"))
+	assert.Equal(Schema{
+		QueryType: Type{Name: "Query"},
+		Types: []Type{
+			{Kind: "SCALAR", Name: "Date", SpecifiedByURL: "https://example.com"},
+			{Kind: "ENUM", Name: "Color", EnumValues: []EnumValue{
+				{Name: "RED"},
+				{Name: "BLUE", IsDeprecated: true, DeprecationReason: "no"},
+			}},
+			{Kind: "INPUT_OBJECT", Name: "Filter", InputFields: []Arg{
+				{Name: "color", Type: Type{Kind: "ENUM", Name: "Color"}, DefaultValue: "RED"},
+			}},
+		},
+		Directives: []Directive{
+			{Name: "tag", Locations: []string{"FIELD"}, IsRepeatable: true, Args: []Arg{}},
+		},
+	}, schema)
+}
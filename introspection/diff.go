@@ -0,0 +1,426 @@
+package introspection
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Criticality tells how a schema change affects existing clients
+type Criticality string
+
+const (
+	// Breaking change makes existing queries invalid
+	Breaking Criticality = "breaking"
+	// Dangerous change keeps queries valid, but may change
+	// their results in a way clients do not expect
+	Dangerous Criticality = "dangerous"
+	// Safe change does not affect existing clients
+	Safe Criticality = "safe"
+)
+
+// ChangeType identifies kind of schema change
+type ChangeType string
+
+// Types of schema changes found by Diff
+const (
+	TypeRemoved                ChangeType = "TYPE_REMOVED"
+	TypeAdded                  ChangeType = "TYPE_ADDED"
+	TypeKindChanged            ChangeType = "TYPE_KIND_CHANGED"
+	RootTypeChanged            ChangeType = "ROOT_TYPE_CHANGED"
+	FieldRemoved               ChangeType = "FIELD_REMOVED"
+	FieldAdded                 ChangeType = "FIELD_ADDED"
+	FieldTypeChanged           ChangeType = "FIELD_TYPE_CHANGED"
+	FieldDeprecated            ChangeType = "FIELD_DEPRECATED"
+	ArgRemoved                 ChangeType = "ARG_REMOVED"
+	ArgAdded                   ChangeType = "ARG_ADDED"
+	ArgTypeChanged             ChangeType = "ARG_TYPE_CHANGED"
+	ArgDefaultChanged          ChangeType = "ARG_DEFAULT_CHANGED"
+	InputFieldRemoved          ChangeType = "INPUT_FIELD_REMOVED"
+	InputFieldAdded            ChangeType = "INPUT_FIELD_ADDED"
+	InputFieldTypeChanged      ChangeType = "INPUT_FIELD_TYPE_CHANGED"
+	InputFieldDefaultChanged   ChangeType = "INPUT_FIELD_DEFAULT_CHANGED"
+	EnumValueRemoved           ChangeType = "ENUM_VALUE_REMOVED"
+	EnumValueAdded             ChangeType = "ENUM_VALUE_ADDED"
+	EnumValueDeprecated        ChangeType = "ENUM_VALUE_DEPRECATED"
+	UnionMemberRemoved         ChangeType = "UNION_MEMBER_REMOVED"
+	UnionMemberAdded           ChangeType = "UNION_MEMBER_ADDED"
+	InterfaceRemoved           ChangeType = "INTERFACE_REMOVED"
+	InterfaceAdded             ChangeType = "INTERFACE_ADDED"
+	DirectiveRemoved           ChangeType = "DIRECTIVE_REMOVED"
+	DirectiveAdded             ChangeType = "DIRECTIVE_ADDED"
+	DirectiveLocationRemoved   ChangeType = "DIRECTIVE_LOCATION_REMOVED"
+	DirectiveLocationAdded     ChangeType = "DIRECTIVE_LOCATION_ADDED"
+	DirectiveRepeatableRemoved ChangeType = "DIRECTIVE_REPEATABLE_REMOVED"
+)
+
+// Change is a single difference between two schemas
+type Change struct {
+	// Criticality of change
+	Criticality Criticality `json:"criticality"`
+	// Type of change
+	Type ChangeType `json:"type"`
+	// Path to changed element, such as Type.field(arg:)
+	Path string `json:"path"`
+	// Message is a human readable description of change
+	Message string `json:"message"`
+}
+
+// Changes is a list of schema changes
+type Changes []Change
+
+// Breaking returns true if any of changes is breaking
+func (c Changes) Breaking() bool {
+	for _, ch := range c {
+		if ch.Criticality == Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+type differ struct {
+	changes Changes
+}
+
+func (d *differ) add(crit Criticality, typ ChangeType, path, format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{
+		Criticality: crit,
+		Type:        typ,
+		Path:        path,
+		Message:     fmt.Sprintf(format, args...),
+	})
+}
+
+// safeOutputChange returns true if clients expecting value of
+// type o can handle value of type n
+func safeOutputChange(o, n Type) bool {
+	switch {
+	case o.NonNull():
+		return n.NonNull() && safeOutputChange(*o.OfType, *n.OfType)
+	case n.NonNull():
+		return safeOutputChange(o, *n.OfType)
+	case o.List():
+		return n.List() && safeOutputChange(*o.OfType, *n.OfType)
+	}
+	return !n.List() && o.Name == n.Name
+}
+
+// safeInputChange returns true if every value clients send
+// as type o is still a valid value of type n
+func safeInputChange(o, n Type) bool {
+	switch {
+	case n.NonNull():
+		return o.NonNull() && safeInputChange(*o.OfType, *n.OfType)
+	case o.NonNull():
+		return safeInputChange(*o.OfType, n)
+	case o.List():
+		return n.List() && safeInputChange(*o.OfType, *n.OfType)
+	}
+	return !n.List() && o.Name == n.Name
+}
+
+// required returns true if input value must be set by client
+func required(a Arg) bool {
+	return a.Type.NonNull() && a.DefaultValue == ""
+}
+
+func typeMap(types []Type) map[string]Type {
+	m := make(map[string]Type, len(types))
+	for _, t := range types {
+		if !t.Builtin() {
+			m[t.Name] = t
+		}
+	}
+	return m
+}
+
+func sortedKeys(m map[string]Type) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func kindName(t Type) string {
+	return strings.ToLower(strings.Replace(t.Kind, "_", " ", -1))
+}
+
+func (d *differ) roots(o, n Schema) {
+	roots := []struct {
+		op   string
+		o, n Type
+	}{
+		{"query", o.QueryType, n.QueryType},
+		{"mutation", o.MutationType, n.MutationType},
+		{"subscription", o.SubscriptionType, n.SubscriptionType},
+	}
+	for _, r := range roots {
+		switch {
+		case r.o.Name == "" || r.o.Name == r.n.Name:
+		case r.n.Name == "":
+			d.add(Breaking, RootTypeChanged, r.op, "Root %s type %s was removed", r.op, r.o.Name)
+		default:
+			d.add(Breaking, RootTypeChanged, r.op, "Root %s type changed from %s to %s", r.op, r.o.Name, r.n.Name)
+		}
+	}
+}
+
+func (d *differ) types(o, n Schema) {
+	ot := typeMap(o.Types)
+	nt := typeMap(n.Types)
+	for _, name := range sortedKeys(ot) {
+		t := ot[name]
+		u, ok := nt[name]
+		switch {
+		case !ok:
+			d.add(Breaking, TypeRemoved, name, "Type %s was removed", name)
+		case t.Kind != u.Kind:
+			d.add(Breaking, TypeKindChanged, name, "Type %s changed from %s to %s", name, kindName(t), kindName(u))
+		default:
+			d.typeDef(t, u)
+		}
+	}
+	for _, name := range sortedKeys(nt) {
+		if _, ok := ot[name]; !ok {
+			d.add(Safe, TypeAdded, name, "Type %s was added", name)
+		}
+	}
+}
+
+func (d *differ) typeDef(o, n Type) {
+	switch {
+	case o.Object(), o.Interface():
+		d.fields(o, n)
+		d.members(o.Name, o.Interfaces, n.Interfaces, InterfaceRemoved, InterfaceAdded, "Interface")
+	case o.Union():
+		d.members(o.Name, o.PossibleTypes, n.PossibleTypes, UnionMemberRemoved, UnionMemberAdded, "Member")
+	case o.Enum():
+		d.enumValues(o, n)
+	case o.Input():
+		d.inputFields(o, n)
+	}
+}
+
+func (d *differ) fields(o, n Type) {
+	nf := make(map[string]Field, len(n.Fields))
+	for _, f := range n.Fields {
+		nf[f.Name] = f
+	}
+	of := make(map[string]bool, len(o.Fields))
+	for _, f := range o.Fields {
+		of[f.Name] = true
+		path := o.Name + "." + f.Name
+		g, ok := nf[f.Name]
+		if !ok {
+			d.add(Breaking, FieldRemoved, path, "Field %s was removed", path)
+			continue
+		}
+		if !safeOutputChange(f.Type, g.Type) {
+			d.add(Breaking, FieldTypeChanged, path, "Field %s changed type from %s to %s", path, f.Type.GoString(), g.Type.GoString())
+		} else if f.Type.GoString() != g.Type.GoString() {
+			d.add(Safe, FieldTypeChanged, path, "Field %s changed type from %s to %s", path, f.Type.GoString(), g.Type.GoString())
+		}
+		if !f.IsDeprecated && g.IsDeprecated {
+			d.add(Safe, FieldDeprecated, path, "Field %s was deprecated", path)
+		}
+		d.args(path, f.Args, g.Args)
+	}
+	for _, f := range n.Fields {
+		if !of[f.Name] {
+			path := o.Name + "." + f.Name
+			d.add(Safe, FieldAdded, path, "Field %s was added", path)
+		}
+	}
+}
+
+func (d *differ) args(path string, o, n []Arg) {
+	d.inputValues(path+"(", ":)", "Argument", o, n, inputValueChanges{
+		removed:        ArgRemoved,
+		added:          ArgAdded,
+		typeChanged:    ArgTypeChanged,
+		defaultChanged: ArgDefaultChanged,
+	})
+}
+
+func (d *differ) inputFields(o, n Type) {
+	d.inputValues(o.Name+".", "", "Input field", o.InputFields, n.InputFields, inputValueChanges{
+		removed:        InputFieldRemoved,
+		added:          InputFieldAdded,
+		typeChanged:    InputFieldTypeChanged,
+		defaultChanged: InputFieldDefaultChanged,
+	})
+}
+
+type inputValueChanges struct {
+	removed, added, typeChanged, defaultChanged ChangeType
+}
+
+// sameValue returns true if literals a and b are the same value,
+// ignoring formatting and order of input object fields, which
+// differ between SDL and introspection of the same schema
+func sameValue(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if a == "" || b == "" {
+		return false
+	}
+	av, err := ParseValue(a)
+	if err != nil {
+		return false
+	}
+	bv, err := ParseValue(b)
+	if err != nil {
+		return false
+	}
+	// objects are printed with sorted keys
+	ab, _ := json.Marshal(av)
+	bb, _ := json.Marshal(bv)
+	return string(ab) == string(bb)
+}
+
+func (d *differ) inputValues(prefix, suffix, what string, o, n []Arg, ct inputValueChanges) {
+	nv := make(map[string]Arg, len(n))
+	for _, a := range n {
+		nv[a.Name] = a
+	}
+	ov := make(map[string]bool, len(o))
+	for _, a := range o {
+		ov[a.Name] = true
+		path := prefix + a.Name + suffix
+		b, ok := nv[a.Name]
+		if !ok {
+			d.add(Breaking, ct.removed, path, "%s %s was removed", what, path)
+			continue
+		}
+		if !safeInputChange(a.Type, b.Type) {
+			d.add(Breaking, ct.typeChanged, path, "%s %s changed type from %s to %s", what, path, a.Type.GoString(), b.Type.GoString())
+		} else if a.Type.GoString() != b.Type.GoString() {
+			d.add(Safe, ct.typeChanged, path, "%s %s changed type from %s to %s", what, path, a.Type.GoString(), b.Type.GoString())
+		}
+		if !sameValue(a.DefaultValue, b.DefaultValue) {
+			d.add(Dangerous, ct.defaultChanged, path, "%s %s default value changed from %q to %q", what, path, a.DefaultValue, b.DefaultValue)
+		}
+	}
+	for _, a := range n {
+		if ov[a.Name] {
+			continue
+		}
+		path := prefix + a.Name + suffix
+		if required(a) {
+			d.add(Breaking, ct.added, path, "Required %s %s was added", strings.ToLower(what), path)
+		} else {
+			d.add(Dangerous, ct.added, path, "Optional %s %s was added", strings.ToLower(what), path)
+		}
+	}
+}
+
+func (d *differ) members(name string, o, n []Type, removed, added ChangeType, what string) {
+	nm := make(map[string]bool, len(n))
+	for _, t := range n {
+		nm[t.Name] = true
+	}
+	om := make(map[string]bool, len(o))
+	for _, t := range o {
+		om[t.Name] = true
+		if !nm[t.Name] {
+			d.add(Breaking, removed, name, "%s %s was removed from %s", what, t.Name, name)
+		}
+	}
+	for _, t := range n {
+		if !om[t.Name] {
+			d.add(Dangerous, added, name, "%s %s was added to %s", what, t.Name, name)
+		}
+	}
+}
+
+func (d *differ) enumValues(o, n Type) {
+	nv := make(map[string]EnumValue, len(n.EnumValues))
+	for _, v := range n.EnumValues {
+		nv[v.Name] = v
+	}
+	ov := make(map[string]bool, len(o.EnumValues))
+	for _, v := range o.EnumValues {
+		ov[v.Name] = true
+		path := o.Name + "." + v.Name
+		w, ok := nv[v.Name]
+		switch {
+		case !ok:
+			d.add(Breaking, EnumValueRemoved, path, "Enum value %s was removed", path)
+		case !v.IsDeprecated && w.IsDeprecated:
+			d.add(Safe, EnumValueDeprecated, path, "Enum value %s was deprecated", path)
+		}
+	}
+	for _, v := range n.EnumValues {
+		if !ov[v.Name] {
+			path := o.Name + "." + v.Name
+			d.add(Dangerous, EnumValueAdded, path, "Enum value %s was added", path)
+		}
+	}
+}
+
+func (d *differ) directives(o, n Schema) {
+	nd := make(map[string]Directive, len(n.Directives))
+	for _, dir := range n.Directives {
+		nd[dir.Name] = dir
+	}
+	od := make(map[string]bool, len(o.Directives))
+	for _, dir := range o.Directives {
+		od[dir.Name] = true
+		if dir.Builtin() {
+			continue
+		}
+		path := "@" + dir.Name
+		ndir, ok := nd[dir.Name]
+		if !ok {
+			d.add(Breaking, DirectiveRemoved, path, "Directive %s was removed", path)
+			continue
+		}
+		if dir.IsRepeatable && !ndir.IsRepeatable {
+			d.add(Breaking, DirectiveRepeatableRemoved, path, "Directive %s is no longer repeatable", path)
+		}
+		nl := make(map[string]bool, len(ndir.Locations))
+		for _, l := range ndir.Locations {
+			nl[l] = true
+		}
+		ol := make(map[string]bool, len(dir.Locations))
+		for _, l := range dir.Locations {
+			ol[l] = true
+			if !nl[l] {
+				d.add(Breaking, DirectiveLocationRemoved, path, "Location %s was removed from directive %s", l, path)
+			}
+		}
+		for _, l := range ndir.Locations {
+			if !ol[l] {
+				d.add(Safe, DirectiveLocationAdded, path, "Location %s was added to directive %s", l, path)
+			}
+		}
+		d.args(path, dir.Args, ndir.Args)
+	}
+	for _, dir := range n.Directives {
+		if !od[dir.Name] && !dir.Builtin() {
+			path := "@" + dir.Name
+			d.add(Safe, DirectiveAdded, path, "Directive %s was added", path)
+		}
+	}
+}
+
+// Diff compares two schemas returning list of changes
+// needed to turn o into n, ordered by path of changed
+// element. Types and directives defined by GraphQL
+// specification are not compared, so that schema
+// loaded from SDL can be compared with introspection.
+func Diff(o, n Schema) Changes {
+	d := &differ{}
+	d.roots(o, n)
+	d.types(o, n)
+	d.directives(o, n)
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		return d.changes[i].Path < d.changes[j].Path
+	})
+	return d.changes
+}
@@ -0,0 +1,150 @@
+package introspection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCaseDiff struct {
+	old, new string
+	changes  []Change
+}
+
+func (tt testCaseDiff) test(t *testing.T) {
+	assert := assert.New(t)
+	o, err := ParseSDL(tt.old)
+	if !assert.NoError(err) {
+		return
+	}
+	n, err := ParseSDL(tt.new)
+	if !assert.NoError(err) {
+		return
+	}
+	changes := Diff(o, n)
+	assert.Equal(Changes(tt.changes), changes)
+	breaking := false
+	for _, c := range tt.changes {
+		breaking = breaking || c.Criticality == Breaking
+	}
+	assert.Equal(breaking, changes.Breaking())
+}
+
+func TestDiff(t *testing.T) {
+	tests := map[string]testCaseDiff{
+		"NoChanges": {
+			old: "type Query { a(x: Int): String }",
+			new: "type Query { a(x: Int): String }",
+		},
+		"Fields": {
+			old: "type Query { a: String b: String c: String d: [Int] }",
+			new: "type Query { a: String! b: Int d: [Int!] e: String }",
+			changes: []Change{
+				{Safe, FieldTypeChanged, "Query.a", "Field Query.a changed type from String to String!"},
+				{Breaking, FieldTypeChanged, "Query.b", "Field Query.b changed type from String to Int"},
+				{Breaking, FieldRemoved, "Query.c", "Field Query.c was removed"},
+				{Safe, FieldTypeChanged, "Query.d", "Field Query.d changed type from [Int] to [Int!]"},
+				{Safe, FieldAdded, "Query.e", "Field Query.e was added"},
+			},
+		},
+		"NonNullFieldBecameNullable": {
+			old: "type Query { a: String! }",
+			new: "type Query { a: String @deprecated }",
+			changes: []Change{
+				{Breaking, FieldTypeChanged, "Query.a", "Field Query.a changed type from String! to String"},
+				{Safe, FieldDeprecated, "Query.a", "Field Query.a was deprecated"},
+			},
+		},
+		"Args": {
+			old: "type Query { a(x: Int, y: Int!, z: Int = 1, w: Int): String }",
+			new: "type Query { a(x: Int!, y: Int, z: Int = 2, r: ID!, o: ID, d: ID! = 1): String }",
+			changes: []Change{
+				{Dangerous, ArgAdded, "Query.a(d:)", "Optional argument Query.a(d:) was added"},
+				{Dangerous, ArgAdded, "Query.a(o:)", "Optional argument Query.a(o:) was added"},
+				{Breaking, ArgAdded, "Query.a(r:)", "Required argument Query.a(r:) was added"},
+				{Breaking, ArgRemoved, "Query.a(w:)", "Argument Query.a(w:) was removed"},
+				{Breaking, ArgTypeChanged, "Query.a(x:)", "Argument Query.a(x:) changed type from Int to Int!"},
+				{Safe, ArgTypeChanged, "Query.a(y:)", "Argument Query.a(y:) changed type from Int! to Int"},
+				{Dangerous, ArgDefaultChanged, "Query.a(z:)", `Argument Query.a(z:) default value changed from "1" to "2"`},
+			},
+		},
+		"DefaultFormatting": {
+			old: `type Query { a(x: I = {a: 1, b: ["x"]}, y: I = {a: 1}): Int } input I { a: Int b: [String] }`,
+			new: `type Query { a(x: I = {b: ["x"] a: 1}, y: I = {a: 2}): Int } input I { a: Int b: [String] }`,
+			changes: []Change{
+				{Dangerous, ArgDefaultChanged, "Query.a(y:)", `Argument Query.a(y:) default value changed from "{a: 1}" to "{a: 2}"`},
+			},
+		},
+		"Enums": {
+			old: "type Query { a: E } enum E { A B C }",
+			new: "type Query { a: E } enum E { A @deprecated C D }",
+			changes: []Change{
+				{Safe, EnumValueDeprecated, "E.A", "Enum value E.A was deprecated"},
+				{Breaking, EnumValueRemoved, "E.B", "Enum value E.B was removed"},
+				{Dangerous, EnumValueAdded, "E.D", "Enum value E.D was added"},
+			},
+		},
+		"Unions": {
+			old: "type Query { a: U } union U = A | B type A { a: Int } type B { b: Int } type C { c: Int }",
+			new: "type Query { a: U } union U = A | C type A { a: Int } type B { b: Int } type C { c: Int }",
+			changes: []Change{
+				{Breaking, UnionMemberRemoved, "U", "Member B was removed from U"},
+				{Dangerous, UnionMemberAdded, "U", "Member C was added to U"},
+			},
+		},
+		"Interfaces": {
+			old: "type Query implements A { a: Int } interface A { a: Int } interface B { a: Int }",
+			new: "type Query implements B { a: Int } interface A { a: Int } interface B { a: Int }",
+			changes: []Change{
+				{Breaking, InterfaceRemoved, "Query", "Interface A was removed from Query"},
+				{Dangerous, InterfaceAdded, "Query", "Interface B was added to Query"},
+			},
+		},
+		"Inputs": {
+			old: "type Query { a(i: I): Int } input I { a: Int b: Int c: Int }",
+			new: "type Query { a(i: I): Int } input I { a: [Int] c: Int = 1 d: Int! e: Int }",
+			changes: []Change{
+				{Breaking, InputFieldTypeChanged, "I.a", "Input field I.a changed type from Int to [Int]"},
+				{Breaking, InputFieldRemoved, "I.b", "Input field I.b was removed"},
+				{Dangerous, InputFieldDefaultChanged, "I.c", `Input field I.c default value changed from "" to "1"`},
+				{Breaking, InputFieldAdded, "I.d", "Required input field I.d was added"},
+				{Dangerous, InputFieldAdded, "I.e", "Optional input field I.e was added"},
+			},
+		},
+		"Types": {
+			old: "type Query { a: Int } type A { a: Int } type B { b: Int }",
+			new: "type Query { a: Int } input A { a: Int } type C { c: Int }",
+			changes: []Change{
+				{Breaking, TypeKindChanged, "A", "Type A changed from object to input object"},
+				{Breaking, TypeRemoved, "B", "Type B was removed"},
+				{Safe, TypeAdded, "C", "Type C was added"},
+			},
+		},
+		"Roots": {
+			old: "type Query { a: Int } type Mutation { a: Int }",
+			new: "schema { query: Root } type Root { a: Int } type Mutation { a: Int }",
+			changes: []Change{
+				{Breaking, TypeRemoved, "Query", "Type Query was removed"},
+				{Safe, TypeAdded, "Root", "Type Root was added"},
+				{Breaking, RootTypeChanged, "mutation", "Root mutation type Mutation was removed"},
+				{Breaking, RootTypeChanged, "query", "Root query type changed from Query to Root"},
+			},
+		},
+		"Directives": {
+			old: "type Query { a: Int } directive @a(x: Int) repeatable on FIELD | OBJECT directive @b on FIELD",
+			new: "type Query { a: Int } directive @a(y: Int) on FIELD | QUERY directive @c on FIELD",
+			changes: []Change{
+				{Breaking, DirectiveRepeatableRemoved, "@a", "Directive @a is no longer repeatable"},
+				{Breaking, DirectiveLocationRemoved, "@a", "Location OBJECT was removed from directive @a"},
+				{Safe, DirectiveLocationAdded, "@a", "Location QUERY was added to directive @a"},
+				{Breaking, ArgRemoved, "@a(x:)", "Argument @a(x:) was removed"},
+				{Dangerous, ArgAdded, "@a(y:)", "Optional argument @a(y:) was added"},
+				{Breaking, DirectiveRemoved, "@b", "Directive @b was removed"},
+				{Safe, DirectiveAdded, "@c", "Directive @c was added"},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.test)
+	}
+}
@@ -0,0 +1,443 @@
+package introspection
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+// docKinds lists sections of documentation index in order
+var docKinds = []struct {
+	kind, title string
+}{
+	{"OBJECT", "Objects"},
+	{"INTERFACE", "Interfaces"},
+	{"UNION", "Unions"},
+	{"ENUM", "Enums"},
+	{"INPUT_OBJECT", "Input objects"},
+	{"SCALAR", "Scalars"},
+}
+
+// docs holds schema data shared by documentation printers
+type docs struct {
+	schema Schema
+	// types documented, builtin types are not documented
+	types map[string]Type
+	names []string
+	// implementations of each of interfaces
+	implementations map[string][]string
+}
+
+func newDocs(s Schema) *docs {
+	d := &docs{
+		schema:          s,
+		types:           typeMap(s.Types),
+		implementations: map[string][]string{},
+	}
+	d.names = sortedKeys(d.types)
+	for _, name := range d.names {
+		for _, i := range d.types[name].Interfaces {
+			d.implementations[i.Name] = append(d.implementations[i.Name], name)
+		}
+	}
+	return d
+}
+
+func (d *docs) directives() []Directive {
+	var directives []Directive
+	for _, dir := range d.schema.Directives {
+		if !dir.Builtin() {
+			directives = append(directives, dir)
+		}
+	}
+	sort.Slice(directives, func(i, j int) bool {
+		return directives[i].Name < directives[j].Name
+	})
+	return directives
+}
+
+func (d *docs) roots() [][2]string {
+	var roots [][2]string
+	for _, r := range [][2]string{
+		{"Query", d.schema.QueryType.Name},
+		{"Mutation", d.schema.MutationType.Name},
+		{"Subscription", d.schema.SubscriptionType.Name},
+	} {
+		if r[1] != "" {
+			roots = append(roots, r)
+		}
+	}
+	return roots
+}
+
+// typeRef formats reference to type t, link formats
+// named types documented in schema
+func (d *docs) typeRef(t Type, link func(name string) string, open, close string) string {
+	switch {
+	case t.NonNull():
+		return d.typeRef(*t.OfType, link, open, close) + "!"
+	case t.List():
+		return open + d.typeRef(*t.OfType, link, open, close) + close
+	}
+	if _, ok := d.types[t.Name]; ok {
+		return link(t.Name)
+	}
+	return t.Name
+}
+
+func firstLine(s string) string {
+	return strings.TrimSpace(strings.SplitN(s, "\n", 2)[0])
+}
+
+func deprecationReason(reason string) string {
+	if reason == "" {
+		return DefaultDeprecationReason
+	}
+	return reason
+}
+
+type markdownPrinter struct {
+	bytes.Buffer
+	*docs
+}
+
+func mdLink(name string) string {
+	return "[" + name + "](" + name + ".md)"
+}
+
+func (p *markdownPrinter) typeRef(t Type) string {
+	return p.docs.typeRef(t, mdLink, `\[`, `\]`)
+}
+
+// cell escapes s for use in Markdown table cell
+func (p *markdownPrinter) cell(s string) string {
+	s = strings.Replace(s, "|", `\|`, -1)
+	return strings.Replace(strings.TrimSpace(s), "\n", "<br>", -1)
+}
+
+// descriptionCell formats description for table cell,
+// preceded by deprecation notice if deprecated
+func (p *markdownPrinter) descriptionCell(desc string, isDeprecated bool, reason string) string {
+	desc = p.cell(desc)
+	if !isDeprecated {
+		return desc
+	}
+	notice := "**Deprecated:** " + p.cell(deprecationReason(reason))
+	if desc == "" {
+		return notice
+	}
+	return notice + "<br>" + desc
+}
+
+func (p *markdownPrinter) paragraph(s string) {
+	if s != "" {
+		p.WriteString(s + "\n\n")
+	}
+}
+
+func (p *markdownPrinter) deprecation(isDeprecated bool, reason string) {
+	if isDeprecated {
+		p.WriteString("> **Deprecated:** " + deprecationReason(reason) + "\n\n")
+	}
+}
+
+func (p *markdownPrinter) inputValues(title, prefix string, args []Arg) {
+	if len(args) == 0 {
+		return
+	}
+	fmt.Fprintf(p, "| %s | Type | Default | Description |\n| --- | --- | --- | --- |\n", title)
+	for _, a := range args {
+		def := ""
+		if a.DefaultValue != "" {
+			def = "`" + p.cell(a.DefaultValue) + "`"
+		}
+		desc := p.descriptionCell(a.Description, a.IsDeprecated, a.DeprecationReason)
+		fmt.Fprintf(p, "| <a id=\"%s\"></a>`%s` | %s | %s | %s |\n", prefix+a.Name, a.Name, p.typeRef(a.Type), def, desc)
+	}
+	p.WriteString("\n")
+}
+
+func (p *markdownPrinter) index() string {
+	p.Reset()
+	p.WriteString("# Schema\n\n")
+	p.paragraph(p.schema.Description)
+	if roots := p.roots(); len(roots) != 0 {
+		p.WriteString("## Operations\n\n")
+		for _, r := range roots {
+			fmt.Fprintf(p, "- %s: %s\n", r[0], mdLink(r[1]))
+		}
+		p.WriteString("\n")
+	}
+	for _, k := range docKinds {
+		header := false
+		for _, name := range p.names {
+			t := p.types[name]
+			if t.Kind != k.kind {
+				continue
+			}
+			if !header {
+				p.WriteString("## " + k.title + "\n\n")
+				header = true
+			}
+			p.WriteString("- " + mdLink(name))
+			if desc := firstLine(t.Description); desc != "" {
+				p.WriteString(" - " + desc)
+			}
+			p.WriteString("\n")
+		}
+		if header {
+			p.WriteString("\n")
+		}
+	}
+	if directives := p.directives(); len(directives) != 0 {
+		p.WriteString("## Directives\n\n")
+		for _, dir := range directives {
+			fmt.Fprintf(p, "<a id=\"@%s\"></a>\n### @%s\n\n", dir.Name, dir.Name)
+			p.paragraph(dir.Description)
+			p.WriteString("Locations: " + strings.Join(dir.Locations, ", "))
+			if dir.IsRepeatable {
+				p.WriteString(" (repeatable)")
+			}
+			p.WriteString("\n\n")
+			p.inputValues("Argument", "@"+dir.Name+".", dir.Args)
+		}
+	}
+	return strings.TrimSuffix(p.String(), "\n")
+}
+
+func (p *markdownPrinter) links(names []string) string {
+	links := make([]string, 0, len(names))
+	for _, name := range names {
+		links = append(links, p.typeRef(Type{Name: name}))
+	}
+	return strings.Join(links, ", ")
+}
+
+func typeNames(types []Type) []string {
+	names := make([]string, 0, len(types))
+	for _, t := range types {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// kindLine describes kind of type and its relations to other types
+func (d *docs) kindLine(t Type, links func([]string) string) string {
+	line := kindName(t)
+	line = strings.ToUpper(line[:1]) + line[1:]
+	if len(t.Interfaces) != 0 {
+		line += " implementing " + links(typeNames(t.Interfaces))
+	}
+	if impl := d.implementations[t.Name]; t.Interface() && len(impl) != 0 {
+		line += " implemented by " + links(impl)
+	}
+	if t.Union() && len(t.PossibleTypes) != 0 {
+		line += " of " + links(typeNames(t.PossibleTypes))
+	}
+	return line
+}
+
+func (p *markdownPrinter) page(t Type) string {
+	p.Reset()
+	p.WriteString("# " + t.Name + "\n\n")
+	p.WriteString(p.kindLine(t, p.links) + "\n\n")
+	p.paragraph(t.Description)
+	if t.SpecifiedByURL != "" {
+		fmt.Fprintf(p, "Specified by <%s>\n\n", t.SpecifiedByURL)
+	}
+	if len(t.Fields) != 0 {
+		p.WriteString("## Fields\n\n")
+		for _, f := range t.Fields {
+			fmt.Fprintf(p, "<a id=\"%s\"></a>\n### %s\n\n", f.Name, f.Name)
+			p.WriteString("Type: " + p.typeRef(f.Type) + "\n\n")
+			p.deprecation(f.IsDeprecated, f.DeprecationReason)
+			p.paragraph(f.Description)
+			p.inputValues("Argument", f.Name+".", f.Args)
+		}
+	}
+	if len(t.InputFields) != 0 {
+		p.WriteString("## Fields\n\n")
+		p.inputValues("Field", "", t.InputFields)
+	}
+	if len(t.EnumValues) != 0 {
+		p.WriteString("## Values\n\n| Value | Description |\n| --- | --- |\n")
+		for _, v := range t.EnumValues {
+			desc := p.descriptionCell(v.Description, v.IsDeprecated, v.DeprecationReason)
+			fmt.Fprintf(p, "| <a id=\"%s\"></a>`%s` | %s |\n", v.Name, v.Name, desc)
+		}
+		p.WriteString("\n")
+	}
+	p.WriteString("[Back to index](README.md)\n")
+	return p.String()
+}
+
+// MarkdownDocs renders reference documentation of schema as
+// Markdown pages, returning page contents by file name. README.md
+// is an index of types and directives, each of types has its own
+// page named after type. Fields, input fields and enum values
+// have anchors named after them, arguments have anchors such
+// as field.arg.
+func (s Schema) MarkdownDocs() map[string]string {
+	p := &markdownPrinter{docs: newDocs(s)}
+	pages := map[string]string{"README.md": p.index()}
+	for _, name := range p.names {
+		pages[name+".md"] = p.page(p.types[name])
+	}
+	return pages
+}
+
+type htmlPrinter struct {
+	bytes.Buffer
+	*docs
+}
+
+func htmlLink(name string) string {
+	return `<a href="#` + html.EscapeString(name) + `">` + html.EscapeString(name) + "</a>"
+}
+
+func (p *htmlPrinter) typeRef(t Type) string {
+	return "<code>" + p.docs.typeRef(t, htmlLink, "[", "]") + "</code>"
+}
+
+func (p *htmlPrinter) links(names []string) string {
+	links := make([]string, 0, len(names))
+	for _, name := range names {
+		links = append(links, p.typeRef(Type{Name: name}))
+	}
+	return strings.Join(links, ", ")
+}
+
+func (p *htmlPrinter) paragraph(s string) {
+	if s != "" {
+		p.WriteString("<p>" + strings.Replace(html.EscapeString(s), "\n", "<br>", -1) + "</p>\n")
+	}
+}
+
+func (p *htmlPrinter) deprecation(isDeprecated bool, reason string) {
+	if isDeprecated {
+		p.WriteString(`<p class="deprecated"><strong>Deprecated:</strong> ` + html.EscapeString(deprecationReason(reason)) + "</p>\n")
+	}
+}
+
+func (p *htmlPrinter) inputValues(title, prefix string, args []Arg) {
+	if len(args) == 0 {
+		return
+	}
+	fmt.Fprintf(p, "<table>\n<tr><th>%s</th><th>Type</th><th>Default</th><th>Description</th></tr>\n", title)
+	for _, a := range args {
+		def := ""
+		if a.DefaultValue != "" {
+			def = "<code>" + html.EscapeString(a.DefaultValue) + "</code>"
+		}
+		fmt.Fprintf(p, `<tr id="%s"><td><code>%s</code></td><td>%s</td><td>%s</td><td>`,
+			html.EscapeString(prefix+a.Name), a.Name, p.typeRef(a.Type), def)
+		p.deprecation(a.IsDeprecated, a.DeprecationReason)
+		p.paragraph(a.Description)
+		p.WriteString("</td></tr>\n")
+	}
+	p.WriteString("</table>\n")
+}
+
+func (p *htmlPrinter) typeDef(t Type) {
+	fmt.Fprintf(p, "<section id=\"%s\">\n<h2>%s</h2>\n", t.Name, t.Name)
+	p.WriteString("<p class=\"kind\">" + p.kindLine(t, p.links) + "</p>\n")
+	p.paragraph(t.Description)
+	if t.SpecifiedByURL != "" {
+		fmt.Fprintf(p, "<p>Specified by <a href=\"%s\">%s</a></p>\n", html.EscapeString(t.SpecifiedByURL), html.EscapeString(t.SpecifiedByURL))
+	}
+	for _, f := range t.Fields {
+		fmt.Fprintf(p, "<h3 id=\"%s.%s\">%s: %s</h3>\n", t.Name, f.Name, f.Name, p.typeRef(f.Type))
+		p.deprecation(f.IsDeprecated, f.DeprecationReason)
+		p.paragraph(f.Description)
+		p.inputValues("Argument", t.Name+"."+f.Name+".", f.Args)
+	}
+	p.inputValues("Field", t.Name+".", t.InputFields)
+	if len(t.EnumValues) != 0 {
+		p.WriteString("<table>\n<tr><th>Value</th><th>Description</th></tr>\n")
+		for _, v := range t.EnumValues {
+			fmt.Fprintf(p, `<tr id="%s.%s"><td><code>%s</code></td><td>`, t.Name, v.Name, v.Name)
+			p.deprecation(v.IsDeprecated, v.DeprecationReason)
+			p.paragraph(v.Description)
+			p.WriteString("</td></tr>\n")
+		}
+		p.WriteString("</table>\n")
+	}
+	p.WriteString("</section>\n")
+}
+
+// HTMLDocs renders reference documentation of schema as a single
+// HTML page. Each of types, fields, arguments and enum values has
+// an anchor named after its coordinate, such as Type.field.arg,
+// directives have anchors such as @directive.
+func (s Schema) HTMLDocs() string {
+	p := &htmlPrinter{docs: newDocs(s)}
+	p.WriteString(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Schema</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: auto; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.5em; text-align: left; vertical-align: top; }
+td p { margin: 0; }
+.deprecated { color: #a33; }
+.kind { color: #666; }
+</style>
+</head>
+<body>
+<h1>Schema</h1>
+`)
+	p.paragraph(s.Description)
+	p.WriteString("<nav>\n")
+	if roots := p.roots(); len(roots) != 0 {
+		p.WriteString("<h2>Operations</h2>\n<ul>\n")
+		for _, r := range roots {
+			fmt.Fprintf(p, "<li>%s: %s</li>\n", r[0], htmlLink(r[1]))
+		}
+		p.WriteString("</ul>\n")
+	}
+	for _, k := range docKinds {
+		header := false
+		for _, name := range p.names {
+			if p.types[name].Kind != k.kind {
+				continue
+			}
+			if !header {
+				p.WriteString("<h2>" + k.title + "</h2>\n<ul>\n")
+				header = true
+			}
+			p.WriteString("<li>" + htmlLink(name) + "</li>\n")
+		}
+		if header {
+			p.WriteString("</ul>\n")
+		}
+	}
+	directives := p.directives()
+	if len(directives) != 0 {
+		p.WriteString("<h2>Directives</h2>\n<ul>\n")
+		for _, dir := range directives {
+			p.WriteString("<li>" + htmlLink("@"+dir.Name) + "</li>\n")
+		}
+		p.WriteString("</ul>\n")
+	}
+	p.WriteString("</nav>\n")
+	for _, name := range p.names {
+		p.typeDef(p.types[name])
+	}
+	for _, dir := range directives {
+		fmt.Fprintf(p, "<section id=\"@%s\">\n<h2>@%s</h2>\n", dir.Name, dir.Name)
+		p.paragraph(dir.Description)
+		p.WriteString("<p>Locations: " + strings.Join(dir.Locations, ", "))
+		if dir.IsRepeatable {
+			p.WriteString(" (repeatable)")
+		}
+		p.WriteString("</p>\n")
+		p.inputValues("Argument", "@"+dir.Name+".", dir.Args)
+		p.WriteString("</section>\n")
+	}
+	p.WriteString("</body>\n</html>\n")
+	return p.String()
+}
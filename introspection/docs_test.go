@@ -0,0 +1,171 @@
+package introspection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const docsSchema = `
+"Root query"
+type Query {
+  "Find user"
+  user(id: ID!, "Results | page" first: Int = 10 @deprecated(reason: "Use page")): User
+  old: String @deprecated
+  search: [Result!]
+}
+
+"A node"
+interface Node {
+  id: ID!
+}
+
+type User implements Node {
+  id: ID!
+  color: Color
+}
+
+union Result = User
+
+enum Color {
+  RED
+  "Legacy"
+  GREEN @deprecated
+}
+
+input UserInput {
+  name: String = "x"
+}
+
+"Cache field"
+directive @cached(ttl: Int) repeatable on FIELD_DEFINITION
+`
+
+func TestSchemaMarkdownDocs(t *testing.T) {
+	assert := assert.New(t)
+	s, err := ParseSDL(docsSchema)
+	if !assert.NoError(err) {
+		return
+	}
+	pages := s.MarkdownDocs()
+	var names []string
+	for name := range pages {
+		names = append(names, name)
+	}
+	assert.ElementsMatch([]string{"README.md", "Query.md", "Node.md", "User.md", "Result.md", "Color.md", "UserInput.md"}, names)
+	assert.Equal(`# Schema
+
+## Operations
+
+- Query: [Query](Query.md)
+
+## Objects
+
+- [Query](Query.md) - Root query
+- [User](User.md)
+
+## Interfaces
+
+- [Node](Node.md) - A node
+
+## Unions
+
+- [Result](Result.md)
+
+## Enums
+
+- [Color](Color.md)
+
+## Input objects
+
+- [UserInput](UserInput.md)
+
+## Directives
+
+<a id="@cached"></a>
+### @cached
+
+Cache field
+
+Locations: FIELD_DEFINITION (repeatable)
+
+| Argument | Type | Default | Description |
+| --- | --- | --- | --- |
+| <a id="@cached.ttl"></a>`+"`ttl`"+` | Int |  |  |
+`, pages["README.md"])
+	assert.Equal(`# Query
+
+Object
+
+Root query
+
+## Fields
+
+<a id="user"></a>
+### user
+
+Type: [User](User.md)
+
+Find user
+
+| Argument | Type | Default | Description |
+| --- | --- | --- | --- |
+| <a id="user.id"></a>`+"`id`"+` | ID! |  |  |
+| <a id="user.first"></a>`+"`first`"+` | Int | `+"`10`"+` | **Deprecated:** Use page<br>Results \| page |
+
+<a id="old"></a>
+### old
+
+Type: String
+
+> **Deprecated:** No longer supported
+
+<a id="search"></a>
+### search
+
+Type: \[[Result](Result.md)!\]
+
+[Back to index](README.md)
+`, pages["Query.md"])
+	assert.Equal(`# Color
+
+Enum
+
+## Values
+
+| Value | Description |
+| --- | --- |
+| <a id="RED"></a>`+"`RED`"+` |  |
+| <a id="GREEN"></a>`+"`GREEN`"+` | **Deprecated:** No longer supported<br>Legacy |
+
+[Back to index](README.md)
+`, pages["Color.md"])
+	assert.Contains(pages["Node.md"], "Interface implemented by [User](User.md)\n")
+	assert.Contains(pages["User.md"], "Object implementing [Node](Node.md)\n")
+	assert.Contains(pages["Result.md"], "Union of [User](User.md)\n")
+	assert.Contains(pages["UserInput.md"], "| <a id=\"name\"></a>`name` | String | `\"x\"` |  |\n")
+}
+
+func TestSchemaHTMLDocs(t *testing.T) {
+	assert := assert.New(t)
+	s, err := ParseSDL(docsSchema)
+	if !assert.NoError(err) {
+		return
+	}
+	doc := s.HTMLDocs()
+	for _, part := range []string{
+		`<li>Query: <a href="#Query">Query</a></li>`,
+		`<li><a href="#@cached">@cached</a></li>`,
+		`<section id="Query">`,
+		`<h3 id="Query.user">user: <code><a href="#User">User</a></code></h3>`,
+		`<tr id="Query.user.first"><td><code>first</code></td><td><code>Int</code></td><td><code>10</code></td><td><p class="deprecated"><strong>Deprecated:</strong> Use page</p>` + "\n<p>Results | page</p>",
+		`<h3 id="Query.search">search: <code>[<a href="#Result">Result</a>!]</code></h3>`,
+		`<tr id="Color.GREEN">`,
+		`<tr id="UserInput.name"><td><code>name</code></td><td><code>String</code></td><td><code>&#34;x&#34;</code></td>`,
+		`<p class="kind">Interface implemented by <code><a href="#User">User</a></code></p>`,
+		`<section id="@cached">`,
+		`<tr id="@cached.ttl">`,
+	} {
+		assert.Contains(doc, part)
+	}
+}
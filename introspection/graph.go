@@ -0,0 +1,231 @@
+package introspection
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kinds of graph edges
+const (
+	// EdgeField is an edge from type to type of its field
+	EdgeField = "field"
+	// EdgeImplements is an edge from type to interface it implements
+	EdgeImplements = "implements"
+	// EdgeMember is an edge from union to its member
+	EdgeMember = "member"
+)
+
+// GraphNode is an object, interface or union type in graph
+type GraphNode struct {
+	// Name of type
+	Name string
+	// Kind of type
+	Kind string
+}
+
+// GraphEdge is a relation between two types
+type GraphEdge struct {
+	// From is a name of type edge starts at
+	From string
+	// To is a name of type edge ends at
+	To string
+	// Kind of edge
+	Kind string
+	// Label of edge, a field name for field edges
+	Label string
+}
+
+// Graph is a graph of output types of schema
+type Graph struct {
+	// Nodes sorted by name
+	Nodes []GraphNode
+	// Edges sorted by name of type they start at
+	Edges []GraphEdge
+}
+
+// GraphOptions selects part of schema included in graph
+type GraphOptions struct {
+	// Root limits graph to types reachable from type named Root
+	Root string
+	// Depth limits graph to types at most Depth edges away from
+	// Root, 0 means no limit
+	Depth int
+	// ExcludeIntrospection removes introspection types from graph
+	ExcludeIntrospection bool
+}
+
+// Graph builds type graph of schema. Objects, interfaces and unions
+// are nodes, fields returning them, implemented interfaces and
+// union members are edges. With Root set, graph contains types
+// reachable from root over field and member edges and over
+// implements edges in both directions.
+func (s Schema) Graph(opts GraphOptions) (Graph, error) {
+	var g Graph
+	nodes := map[string]Type{}
+	for _, t := range s.Types {
+		if !t.Object() && !t.Interface() && !t.Union() {
+			continue
+		}
+		if opts.ExcludeIntrospection && strings.HasPrefix(t.Name, "__") {
+			continue
+		}
+		nodes[t.Name] = t
+	}
+	edges := map[string][]GraphEdge{}
+	for name, t := range nodes {
+		for _, f := range t.Fields {
+			if to := f.Type.GetOfTypeLeaf().Name; nodes[to].Name != "" {
+				edges[name] = append(edges[name], GraphEdge{From: name, To: to, Kind: EdgeField, Label: f.Name})
+			}
+		}
+		for _, i := range t.Interfaces {
+			if nodes[i.Name].Name != "" {
+				edges[name] = append(edges[name], GraphEdge{From: name, To: i.Name, Kind: EdgeImplements})
+			}
+		}
+		if t.Union() {
+			for _, m := range t.PossibleTypes {
+				if nodes[m.Name].Name != "" {
+					edges[name] = append(edges[name], GraphEdge{From: name, To: m.Name, Kind: EdgeMember})
+				}
+			}
+		}
+	}
+	include := map[string]bool{}
+	if opts.Root == "" {
+		for name := range nodes {
+			include[name] = true
+		}
+	} else {
+		if nodes[opts.Root].Name == "" {
+			return g, fmt.Errorf("type %s not found", opts.Root)
+		}
+		// implementations of interfaces are reachable from them
+		implementations := map[string][]string{}
+		for name, t := range nodes {
+			for _, i := range t.Interfaces {
+				implementations[i.Name] = append(implementations[i.Name], name)
+			}
+		}
+		depth := map[string]int{opts.Root: 0}
+		queue := []string{opts.Root}
+		for len(queue) != 0 {
+			name := queue[0]
+			queue = queue[1:]
+			include[name] = true
+			if opts.Depth > 0 && depth[name] == opts.Depth {
+				continue
+			}
+			next := implementations[name]
+			for _, e := range edges[name] {
+				next = append(next, e.To)
+			}
+			for _, n := range next {
+				if _, ok := depth[n]; !ok {
+					depth[n] = depth[name] + 1
+					queue = append(queue, n)
+				}
+			}
+		}
+	}
+	names := make([]string, 0, len(include))
+	for name := range include {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		g.Nodes = append(g.Nodes, GraphNode{Name: name, Kind: nodes[name].Kind})
+		for _, e := range edges[name] {
+			if include[e.To] {
+				g.Edges = append(g.Edges, e)
+			}
+		}
+	}
+	return g, nil
+}
+
+// edgesOfKind returns edges of graph of kind
+func (g Graph) edgesOfKind(kind string) []GraphEdge {
+	var edges []GraphEdge
+	for _, e := range g.Edges {
+		if e.Kind == kind {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// DOT formats graph in Graphviz DOT language
+func (g Graph) DOT() string {
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "digraph schema {")
+	fmt.Fprintln(buf, "  rankdir=LR;")
+	fmt.Fprintln(buf, "  node [shape=box];")
+	for _, n := range g.Nodes {
+		switch n.Kind {
+		case "INTERFACE":
+			fmt.Fprintf(buf, "  %q [style=dashed];\n", n.Name)
+		case "UNION":
+			fmt.Fprintf(buf, "  %q [shape=hexagon];\n", n.Name)
+		default:
+			fmt.Fprintf(buf, "  %q;\n", n.Name)
+		}
+	}
+	for _, e := range g.edgesOfKind(EdgeField) {
+		fmt.Fprintf(buf, "  %q -> %q [label=%q];\n", e.From, e.To, e.Label)
+	}
+	if edges := g.edgesOfKind(EdgeImplements); len(edges) != 0 {
+		fmt.Fprintln(buf, "  // implements")
+		for _, e := range edges {
+			fmt.Fprintf(buf, "  %q -> %q [style=dashed, arrowhead=empty];\n", e.From, e.To)
+		}
+	}
+	if edges := g.edgesOfKind(EdgeMember); len(edges) != 0 {
+		fmt.Fprintln(buf, "  // union members")
+		for _, e := range edges {
+			fmt.Fprintf(buf, "  %q -> %q [style=dotted, arrowhead=odiamond];\n", e.From, e.To)
+		}
+	}
+	fmt.Fprintln(buf, "}")
+	return buf.String()
+}
+
+// mermaidID returns node id of type, type names such as
+// end or graph are keywords of Mermaid, so ids are prefixed
+func mermaidID(name string) string {
+	return "n_" + name
+}
+
+// Mermaid formats graph as Mermaid flowchart
+func (g Graph) Mermaid() string {
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "flowchart LR")
+	for _, n := range g.Nodes {
+		switch n.Kind {
+		case "INTERFACE":
+			fmt.Fprintf(buf, "  %s([%q])\n", mermaidID(n.Name), n.Name)
+		case "UNION":
+			fmt.Fprintf(buf, "  %s{{%q}}\n", mermaidID(n.Name), n.Name)
+		default:
+			fmt.Fprintf(buf, "  %s[%q]\n", mermaidID(n.Name), n.Name)
+		}
+	}
+	for _, e := range g.edgesOfKind(EdgeField) {
+		fmt.Fprintf(buf, "  %s -->|%s| %s\n", mermaidID(e.From), e.Label, mermaidID(e.To))
+	}
+	if edges := g.edgesOfKind(EdgeImplements); len(edges) != 0 {
+		fmt.Fprintln(buf, "  %% implements")
+		for _, e := range edges {
+			fmt.Fprintf(buf, "  %s -.->|implements| %s\n", mermaidID(e.From), mermaidID(e.To))
+		}
+	}
+	if edges := g.edgesOfKind(EdgeMember); len(edges) != 0 {
+		fmt.Fprintln(buf, "  %% union members")
+		for _, e := range edges {
+			fmt.Fprintf(buf, "  %s -.-o %s\n", mermaidID(e.From), mermaidID(e.To))
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,194 @@
+package introspection
+
+import (
+	"testing"
+
+	"github.com/aexol/test_util"
+	"github.com/stretchr/testify/assert"
+)
+
+const graphSchema = `
+type Query {
+  viewer: User
+  search(text: String): [Result]
+  ping: String
+}
+
+interface Node {
+  id: ID!
+}
+
+type User implements Node {
+  id: ID!
+  posts: [Post]
+  friend: User
+}
+
+type Post implements Node {
+  id: ID!
+  author: User
+  comments: [Comment]
+}
+
+type Comment {
+  text: String
+}
+
+union Result = User | Post
+
+type __Hidden {
+  user: User
+}
+`
+
+type testCaseGraph struct {
+	opts    GraphOptions
+	dot     string
+	mermaid string
+	err     func(*assert.Assertions) test_util.ErrorAssertion
+}
+
+func (tt testCaseGraph) test(t *testing.T) {
+	assert := assert.New(t)
+	if tt.err == nil {
+		tt.err = test_util.NoError
+	}
+	s, err := ParseSDL(graphSchema)
+	if !assert.NoError(err) {
+		return
+	}
+	g, err := s.Graph(tt.opts)
+	tt.err(assert)(err)
+	if err != nil {
+		return
+	}
+	assert.Equal(tt.dot, g.DOT())
+	if tt.mermaid != "" {
+		assert.Equal(tt.mermaid, g.Mermaid())
+	}
+}
+
+func TestSchemaGraph(t *testing.T) {
+	tests := map[string]testCaseGraph{
+		"All": {
+			opts: GraphOptions{ExcludeIntrospection: true},
+			dot: `digraph schema {
+  rankdir=LR;
+  node [shape=box];
+  "Comment";
+  "Node" [style=dashed];
+  "Post";
+  "Query";
+  "Result" [shape=hexagon];
+  "User";
+  "Post" -> "User" [label="author"];
+  "Post" -> "Comment" [label="comments"];
+  "Query" -> "User" [label="viewer"];
+  "Query" -> "Result" [label="search"];
+  "User" -> "Post" [label="posts"];
+  "User" -> "User" [label="friend"];
+  // implements
+  "Post" -> "Node" [style=dashed, arrowhead=empty];
+  "User" -> "Node" [style=dashed, arrowhead=empty];
+  // union members
+  "Result" -> "User" [style=dotted, arrowhead=odiamond];
+  "Result" -> "Post" [style=dotted, arrowhead=odiamond];
+}
+`,
+			mermaid: `flowchart LR
+  n_Comment["Comment"]
+  n_Node(["Node"])
+  n_Post["Post"]
+  n_Query["Query"]
+  n_Result{{"Result"}}
+  n_User["User"]
+  n_Post -->|author| n_User
+  n_Post -->|comments| n_Comment
+  n_Query -->|viewer| n_User
+  n_Query -->|search| n_Result
+  n_User -->|posts| n_Post
+  n_User -->|friend| n_User
+  %% implements
+  n_Post -.->|implements| n_Node
+  n_User -.->|implements| n_Node
+  %% union members
+  n_Result -.-o n_User
+  n_Result -.-o n_Post
+`,
+		},
+		"Introspection": {
+			opts: GraphOptions{Root: "__Hidden", Depth: 1},
+			dot: `digraph schema {
+  rankdir=LR;
+  node [shape=box];
+  "User";
+  "__Hidden";
+  "User" -> "User" [label="friend"];
+  "__Hidden" -> "User" [label="user"];
+}
+`,
+		},
+		"RootDepth": {
+			opts: GraphOptions{Root: "Node", Depth: 1},
+			dot: `digraph schema {
+  rankdir=LR;
+  node [shape=box];
+  "Node" [style=dashed];
+  "Post";
+  "User";
+  "Post" -> "User" [label="author"];
+  "User" -> "Post" [label="posts"];
+  "User" -> "User" [label="friend"];
+  // implements
+  "Post" -> "Node" [style=dashed, arrowhead=empty];
+  "User" -> "Node" [style=dashed, arrowhead=empty];
+}
+`,
+		},
+		"Root": {
+			opts: GraphOptions{Root: "Post"},
+			dot: `digraph schema {
+  rankdir=LR;
+  node [shape=box];
+  "Comment";
+  "Node" [style=dashed];
+  "Post";
+  "User";
+  "Post" -> "User" [label="author"];
+  "Post" -> "Comment" [label="comments"];
+  "User" -> "Post" [label="posts"];
+  "User" -> "User" [label="friend"];
+  // implements
+  "Post" -> "Node" [style=dashed, arrowhead=empty];
+  "User" -> "Node" [style=dashed, arrowhead=empty];
+}
+`,
+		},
+		"MissingRoot": {
+			opts: GraphOptions{Root: "Missing"},
+			err:  test_util.Error,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.test)
+	}
+}
+
+func TestGraphMermaidKeywords(t *testing.T) {
+	assert := assert.New(t)
+	s, err := ParseSDL("type Query { end: end } type end { graph: graph } type graph { id: ID }")
+	if !assert.NoError(err) {
+		return
+	}
+	g, err := s.Graph(GraphOptions{ExcludeIntrospection: true})
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(`flowchart LR
+  n_Query["Query"]
+  n_end["end"]
+  n_graph["graph"]
+  n_Query -->|end| n_end
+  n_end -->|graph| n_graph
+`, g.Mermaid())
+}
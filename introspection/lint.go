@@ -0,0 +1,360 @@
+package introspection
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Severity of lint problem
+type Severity string
+
+const (
+	// SeverityError marks problems that must be fixed
+	SeverityError Severity = "error"
+	// SeverityWarning marks problems that should be fixed
+	SeverityWarning Severity = "warning"
+	// SeverityInfo marks suggestions
+	SeverityInfo Severity = "info"
+	// SeverityOff disables rule
+	SeverityOff Severity = "off"
+)
+
+// Valid returns true if severity is one of known severities
+func (s Severity) Valid() bool {
+	switch s {
+	case SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		return true
+	}
+	return false
+}
+
+// LintProblem is a single problem found by Lint
+type LintProblem struct {
+	// Rule that found a problem
+	Rule string `json:"rule"`
+	// Severity of problem
+	Severity Severity `json:"severity"`
+	// Path to schema element, such as Type.field(arg:)
+	Path string `json:"path"`
+	// Message is a human readable description of problem
+	Message string `json:"message"`
+}
+
+// LintProblems is a list of lint problems
+type LintProblems []LintProblem
+
+// Errors returns true if any of problems has error severity
+func (p LintProblems) Errors() bool {
+	for _, pr := range p {
+		if pr.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// LintRule is a check run over schema by Lint
+type LintRule struct {
+	// Name of rule used in lint config
+	Name string `json:"name"`
+	// Description of what rule checks
+	Description string `json:"description"`
+	// Severity of rule problems if config does not set it
+	Severity Severity `json:"severity"`
+	check    func(l *linter, s Schema)
+}
+
+// LintRules is a list of rules known to Lint
+var LintRules = []LintRule{
+	{
+		Name:        "description-required",
+		Description: "Types, fields, input fields and enum values have descriptions",
+		Severity:    SeverityWarning,
+		check:       lintDescriptions,
+	},
+	{
+		Name:        "deprecation-reason",
+		Description: "Deprecated fields, arguments and enum values have a deprecation reason",
+		Severity:    SeverityWarning,
+		check:       lintDeprecationReasons,
+	},
+	{
+		Name:        "field-camel-case",
+		Description: "Fields, input fields and arguments are named in camelCase",
+		Severity:    SeverityError,
+		check:       lintFieldNames,
+	},
+	{
+		Name:        "enum-upper-case",
+		Description: "Enum values are named in UPPER_CASE",
+		Severity:    SeverityError,
+		check:       lintEnumValueNames,
+	},
+	{
+		Name:        "input-suffix",
+		Description: "Input object type names end with Input",
+		Severity:    SeverityWarning,
+		check:       lintInputNames,
+	},
+	{
+		Name:        "relay-connection",
+		Description: "Connection types follow Relay cursor connections specification",
+		Severity:    SeverityError,
+		check:       lintConnections,
+	},
+}
+
+// LintConfig selects rules run by Lint
+type LintConfig struct {
+	// Rules maps rule name to its severity, rules missing
+	// from map are run with their default severity
+	Rules map[string]Severity `json:"rules"`
+}
+
+type linter struct {
+	rule     string
+	severity Severity
+	problems LintProblems
+}
+
+func (l *linter) report(path, format string, args ...interface{}) {
+	l.problems = append(l.problems, LintProblem{
+		Rule:     l.rule,
+		Severity: l.severity,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Lint runs rules selected by config over schema
+// and returns problems sorted by path
+func Lint(s Schema, config LintConfig) (LintProblems, error) {
+	known := make(map[string]bool, len(LintRules))
+	for _, r := range LintRules {
+		known[r.Name] = true
+	}
+	for name, severity := range config.Rules {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown lint rule %q", name)
+		}
+		if !severity.Valid() {
+			return nil, fmt.Errorf("invalid severity %q of lint rule %q", severity, name)
+		}
+	}
+	l := &linter{}
+	for _, r := range LintRules {
+		l.rule, l.severity = r.Name, r.Severity
+		if severity, ok := config.Rules[r.Name]; ok {
+			l.severity = severity
+		}
+		if l.severity != SeverityOff {
+			r.check(l, s)
+		}
+	}
+	sort.SliceStable(l.problems, func(i, j int) bool {
+		return l.problems[i].Path < l.problems[j].Path
+	})
+	return l.problems, nil
+}
+
+// lintTypes calls f for each of non builtin types
+// in schema, sorted by name
+func lintTypes(s Schema, f func(t Type)) {
+	types := typeMap(s.Types)
+	for _, name := range sortedKeys(types) {
+		f(types[name])
+	}
+}
+
+func lintDescriptions(l *linter, s Schema) {
+	lintTypes(s, func(t Type) {
+		if t.Description == "" {
+			l.report(t.Name, "Type %s has no description", t.Name)
+		}
+		for _, f := range t.Fields {
+			if f.Description == "" {
+				l.report(t.Name+"."+f.Name, "Field %s.%s has no description", t.Name, f.Name)
+			}
+		}
+		for _, f := range t.InputFields {
+			if f.Description == "" {
+				l.report(t.Name+"."+f.Name, "Input field %s.%s has no description", t.Name, f.Name)
+			}
+		}
+		for _, v := range t.EnumValues {
+			if v.Description == "" {
+				l.report(t.Name+"."+v.Name, "Enum value %s.%s has no description", t.Name, v.Name)
+			}
+		}
+	})
+}
+
+func noDeprecationReason(deprecated bool, reason string) bool {
+	return deprecated && (reason == "" || reason == DefaultDeprecationReason)
+}
+
+func lintDeprecationReasons(l *linter, s Schema) {
+	lintTypes(s, func(t Type) {
+		for _, f := range t.Fields {
+			path := t.Name + "." + f.Name
+			if noDeprecationReason(f.IsDeprecated, f.DeprecationReason) {
+				l.report(path, "Field %s is deprecated without a reason", path)
+			}
+			for _, a := range f.Args {
+				if noDeprecationReason(a.IsDeprecated, a.DeprecationReason) {
+					l.report(path+"("+a.Name+":)", "Argument %s(%s:) is deprecated without a reason", path, a.Name)
+				}
+			}
+		}
+		for _, f := range t.InputFields {
+			if noDeprecationReason(f.IsDeprecated, f.DeprecationReason) {
+				l.report(t.Name+"."+f.Name, "Input field %s.%s is deprecated without a reason", t.Name, f.Name)
+			}
+		}
+		for _, v := range t.EnumValues {
+			if noDeprecationReason(v.IsDeprecated, v.DeprecationReason) {
+				l.report(t.Name+"."+v.Name, "Enum value %s.%s is deprecated without a reason", t.Name, v.Name)
+			}
+		}
+	})
+}
+
+var (
+	camelCase = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+	upperCase = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+)
+
+func lintFieldNames(l *linter, s Schema) {
+	lintTypes(s, func(t Type) {
+		for _, f := range t.Fields {
+			path := t.Name + "." + f.Name
+			if !camelCase.MatchString(f.Name) {
+				l.report(path, "Field %s is not in camelCase", path)
+			}
+			for _, a := range f.Args {
+				if !camelCase.MatchString(a.Name) {
+					l.report(path+"("+a.Name+":)", "Argument %s(%s:) is not in camelCase", path, a.Name)
+				}
+			}
+		}
+		for _, f := range t.InputFields {
+			if !camelCase.MatchString(f.Name) {
+				l.report(t.Name+"."+f.Name, "Input field %s.%s is not in camelCase", t.Name, f.Name)
+			}
+		}
+	})
+}
+
+func lintEnumValueNames(l *linter, s Schema) {
+	lintTypes(s, func(t Type) {
+		for _, v := range t.EnumValues {
+			if !upperCase.MatchString(v.Name) {
+				l.report(t.Name+"."+v.Name, "Enum value %s.%s is not in UPPER_CASE", t.Name, v.Name)
+			}
+		}
+	})
+}
+
+func lintInputNames(l *linter, s Schema) {
+	lintTypes(s, func(t Type) {
+		if t.Input() && !strings.HasSuffix(t.Name, "Input") {
+			l.report(t.Name, "Input type %s does not end with Input", t.Name)
+		}
+	})
+}
+
+func fieldByName(t Type, name string) (Field, bool) {
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// connection returns true if t is an object type
+// named like a Relay connection
+func connection(t Type) bool {
+	return t.Object() && strings.HasSuffix(t.Name, "Connection") && t.Name != "Connection"
+}
+
+// listType returns true if t is a list or a non null list
+func listType(t Type) bool {
+	if t.NonNull() {
+		t = *t.OfType
+	}
+	return t.List()
+}
+
+// scalarField returns true if t has field name
+// returning a scalar that is not a list
+func scalarField(s Schema, t Type, name string) bool {
+	f, ok := fieldByName(t, name)
+	return ok && !listType(f.Type) && f.Type.GetOfTypeLeaf().Deref(s.Types).Scalar()
+}
+
+func lintConnections(l *linter, s Schema) {
+	edges := map[string]bool{}
+	lintTypes(s, func(t Type) {
+		for _, f := range t.Fields {
+			if !connection(f.Type.GetOfTypeLeaf().Deref(s.Types)) {
+				continue
+			}
+			args := make(map[string]bool, len(f.Args))
+			for _, a := range f.Args {
+				args[a.Name] = true
+			}
+			if !(args["first"] && args["after"]) && !(args["last"] && args["before"]) {
+				l.report(
+					t.Name+"."+f.Name,
+					"Field %s.%s returns a connection, but has neither first and after nor last and before arguments",
+					t.Name, f.Name,
+				)
+			}
+		}
+		if !connection(t) {
+			return
+		}
+		f, ok := fieldByName(t, "edges")
+		edge := f.Type.GetOfTypeLeaf().Deref(s.Types)
+		if !ok || !listType(f.Type) || !edge.Object() {
+			l.report(t.Name, "Connection %s must have field edges returning a list of edge objects", t.Name)
+		} else {
+			edges[edge.Name] = true
+		}
+		if f, ok := fieldByName(t, "pageInfo"); !ok || f.Type.GoString() != "PageInfo!" {
+			l.report(t.Name, "Connection %s must have field pageInfo of type PageInfo!", t.Name)
+		}
+	})
+	lintTypes(s, func(t Type) {
+		if !edges[t.Name] {
+			return
+		}
+		if f, ok := fieldByName(t, "node"); !ok || listType(f.Type) {
+			l.report(t.Name, "Edge %s must have field node that does not return a list", t.Name)
+		}
+		if !scalarField(s, t, "cursor") {
+			l.report(t.Name, "Edge %s must have field cursor returning a scalar", t.Name)
+		}
+	})
+	if len(edges) == 0 {
+		return
+	}
+	pageInfo := Type{Name: "PageInfo"}.Deref(s.Types)
+	if !pageInfo.Object() {
+		l.report("PageInfo", "Type PageInfo must be an object")
+		return
+	}
+	for _, name := range []string{"hasPreviousPage", "hasNextPage"} {
+		if f, ok := fieldByName(pageInfo, name); !ok || f.Type.GoString() != "Boolean!" {
+			l.report("PageInfo", "PageInfo must have field %s of type Boolean!", name)
+		}
+	}
+	for _, name := range []string{"startCursor", "endCursor"} {
+		if !scalarField(s, pageInfo, name) {
+			l.report("PageInfo", "PageInfo must have field %s returning a scalar", name)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package introspection
+
+import (
+	"testing"
+
+	"github.com/aexol/test_util"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCaseLint struct {
+	sdl      string
+	rules    map[string]Severity
+	problems LintProblems
+	err      func(*assert.Assertions) test_util.ErrorAssertion
+}
+
+func (tt testCaseLint) test(t *testing.T) {
+	assert := assert.New(t)
+	if tt.err == nil {
+		tt.err = test_util.NoError
+	}
+	s, err := ParseSDL(tt.sdl)
+	if !assert.NoError(err) {
+		return
+	}
+	// run only rules selected by test case
+	rules := map[string]Severity{}
+	for _, r := range LintRules {
+		rules[r.Name] = SeverityOff
+	}
+	for name, severity := range tt.rules {
+		rules[name] = severity
+	}
+	problems, err := Lint(s, LintConfig{Rules: rules})
+	tt.err(assert)(err)
+	assert.Equal(tt.problems, problems)
+	assert.Equal(tt.problems.Errors(), problems.Errors())
+}
+
+func TestLint(t *testing.T) {
+	tests := map[string]testCaseLint{
+		"Descriptions": {
+			sdl: `"q" type Query { "a" a(x: Int): E b: Int } enum E { "A" A B } input I { i: Int }`,
+			rules: map[string]Severity{
+				"description-required": SeverityWarning,
+			},
+			problems: LintProblems{
+				{"description-required", SeverityWarning, "E", "Type E has no description"},
+				{"description-required", SeverityWarning, "E.B", "Enum value E.B has no description"},
+				{"description-required", SeverityWarning, "I", "Type I has no description"},
+				{"description-required", SeverityWarning, "I.i", "Input field I.i has no description"},
+				{"description-required", SeverityWarning, "Query.b", "Field Query.b has no description"},
+			},
+		},
+		"DeprecationReason": {
+			sdl: `type Query { a: Int @deprecated b: Int @deprecated(reason: "use a") c(x: Int @deprecated): E } enum E { A @deprecated B }`,
+			rules: map[string]Severity{
+				"deprecation-reason": SeverityError,
+			},
+			problems: LintProblems{
+				{"deprecation-reason", SeverityError, "E.A", "Enum value E.A is deprecated without a reason"},
+				{"deprecation-reason", SeverityError, "Query.a", "Field Query.a is deprecated without a reason"},
+				{"deprecation-reason", SeverityError, "Query.c(x:)", "Argument Query.c(x:) is deprecated without a reason"},
+			},
+		},
+		"Names": {
+			sdl: `type Query { someField(Some_arg: Int, f: F): E other_field: Int } enum E { UPPER_CASE lower Camel } input F { Value: Int }`,
+			rules: map[string]Severity{
+				"field-camel-case": SeverityError,
+				"enum-upper-case":  SeverityInfo,
+				"input-suffix":     SeverityWarning,
+			},
+			problems: LintProblems{
+				{"enum-upper-case", SeverityInfo, "E.Camel", "Enum value E.Camel is not in UPPER_CASE"},
+				{"enum-upper-case", SeverityInfo, "E.lower", "Enum value E.lower is not in UPPER_CASE"},
+				{"input-suffix", SeverityWarning, "F", "Input type F does not end with Input"},
+				{"field-camel-case", SeverityError, "F.Value", "Input field F.Value is not in camelCase"},
+				{"field-camel-case", SeverityError, "Query.other_field", "Field Query.other_field is not in camelCase"},
+				{"field-camel-case", SeverityError, "Query.someField(Some_arg:)", "Argument Query.someField(Some_arg:) is not in camelCase"},
+			},
+		},
+		"ValidConnection": {
+			sdl: `type Query { users(first: Int, after: String): UserConnection! }
+type UserConnection { edges: [UserEdge] pageInfo: PageInfo! }
+type UserEdge { node: User cursor: String! }
+type User { id: ID! }
+type PageInfo { hasPreviousPage: Boolean! hasNextPage: Boolean! startCursor: String endCursor: String }`,
+			rules: map[string]Severity{
+				"relay-connection": SeverityError,
+			},
+		},
+		"InvalidConnection": {
+			sdl: `type Query { users(first: Int, before: String): UserConnection posts: PostConnection }
+type UserConnection { edges: UserEdge pageInfo: PageInfo }
+type PostConnection { edges: [PostEdge!]! pageInfo: PageInfo! }
+type UserEdge { node: User cursor: String! }
+type PostEdge { node: [User] cursor: [String] }
+type User { id: ID! }
+type PageInfo { hasPreviousPage: Boolean hasNextPage: Boolean! startCursor: User }`,
+			rules: map[string]Severity{
+				"relay-connection": SeverityError,
+			},
+			problems: LintProblems{
+				{"relay-connection", SeverityError, "PageInfo", "PageInfo must have field hasPreviousPage of type Boolean!"},
+				{"relay-connection", SeverityError, "PageInfo", "PageInfo must have field startCursor returning a scalar"},
+				{"relay-connection", SeverityError, "PageInfo", "PageInfo must have field endCursor returning a scalar"},
+				{"relay-connection", SeverityError, "PostEdge", "Edge PostEdge must have field node that does not return a list"},
+				{"relay-connection", SeverityError, "PostEdge", "Edge PostEdge must have field cursor returning a scalar"},
+				{"relay-connection", SeverityError, "Query.posts", "Field Query.posts returns a connection, but has neither first and after nor last and before arguments"},
+				{"relay-connection", SeverityError, "Query.users", "Field Query.users returns a connection, but has neither first and after nor last and before arguments"},
+				{"relay-connection", SeverityError, "UserConnection", "Connection UserConnection must have field edges returning a list of edge objects"},
+				{"relay-connection", SeverityError, "UserConnection", "Connection UserConnection must have field pageInfo of type PageInfo!"},
+			},
+		},
+		"UnknownRule": {
+			sdl:   `type Query { a: Int }`,
+			rules: map[string]Severity{"missing": SeverityError},
+			err:   test_util.Error,
+		},
+		"InvalidSeverity": {
+			sdl:   `type Query { a: Int }`,
+			rules: map[string]Severity{"input-suffix": "fatal"},
+			err:   test_util.Error,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.test)
+	}
+}
+
+func TestLintDefaultSeverity(t *testing.T) {
+	assert := assert.New(t)
+	s, err := ParseSDL(`"q" type Query { "a" a(x: I): Int } "i" input I { "v" Value: Int }`)
+	if !assert.NoError(err) {
+		return
+	}
+	problems, err := Lint(s, LintConfig{})
+	assert.NoError(err)
+	assert.Equal(LintProblems{
+		{"input-suffix", SeverityWarning, "I", "Input type I does not end with Input"},
+		{"field-camel-case", SeverityError, "I.Value", "Input field I.Value is not in camelCase"},
+	}, problems)
+	assert.True(problems.Errors())
+}
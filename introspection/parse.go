@@ -0,0 +1,603 @@
+package introspection
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/printer"
+)
+
+// builtinSDL defines scalars and directives which are a part of
+// every schema, unless schema redefines them
+const builtinSDL = `
+"The String scalar type represents textual data, represented as UTF-8 character sequences."
+scalar String
+
+"The Int scalar type represents non-fractional signed whole numeric values."
+scalar Int
+
+"The Float scalar type represents signed double-precision fractional values."
+scalar Float
+
+"The Boolean scalar type represents true or false."
+scalar Boolean
+
+"The ID scalar type represents a unique identifier."
+scalar ID
+
+"Directs the executor to include this field or fragment only when the if argument is true."
+directive @include("Included when true." if: Boolean!) on FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT
+
+"Directs the executor to skip this field or fragment when the if argument is true."
+directive @skip("Skipped when true." if: Boolean!) on FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT
+
+"Marks an element of a GraphQL schema as no longer supported."
+directive @deprecated(reason: String = "No longer supported") on FIELD_DEFINITION | ARGUMENT_DEFINITION | INPUT_FIELD_DEFINITION | ENUM_VALUE
+
+"Exposes a URL that specifies the behavior of this scalar."
+directive @specifiedBy(url: String!) on SCALAR
+
+"Indicates exactly one field must be supplied and this field must not be null."
+directive @oneOf on INPUT_OBJECT
+`
+
+// sdlExtensions are parts of newer GraphQL SDL that
+// are not understood by parser, and so are removed
+// from source before parsing
+type sdlExtensions struct {
+	// repeatable directives
+	repeatable map[string]bool
+	// interfaces implemented by interfaces
+	interfaces map[string][]string
+}
+
+type sdlToken struct {
+	value      string
+	start, end int
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isName(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
+
+// scanGraphQL splits GraphQL source into names and
+// punctuators, skipping strings, comments and numbers
+func scanGraphQL(sdl string) []sdlToken {
+	var tokens []sdlToken
+	for i := 0; i < len(sdl); {
+		c := sdl[i]
+		switch {
+		case c == '#':
+			for i < len(sdl) && sdl[i] != '\n' {
+				i++
+			}
+		case c == '"' && len(sdl) >= i+3 && sdl[i:i+3] == `"""`:
+			i += 3
+			for i < len(sdl) && !(sdl[i-1] != '\\' && len(sdl) >= i+3 && sdl[i:i+3] == `"""`) {
+				i++
+			}
+			i += 3
+		case c == '"':
+			for i++; i < len(sdl) && sdl[i] != '"' && sdl[i] != '\n'; i++ {
+				if sdl[i] == '\\' {
+					i++
+				}
+			}
+			i++
+		case isNameStart(c):
+			start := i
+			for i < len(sdl) && isName(sdl[i]) {
+				i++
+			}
+			tokens = append(tokens, sdlToken{sdl[start:i], start, i})
+		case isName(c) || c == '-' || c == '.':
+			// numbers and spreads are never interesting
+			i++
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+		default:
+			tokens = append(tokens, sdlToken{sdl[i : i+1], i, i + 1})
+			i++
+		}
+	}
+	return tokens
+}
+
+// stripExtensions removes repeatable directives and interfaces
+// implementing interfaces from sdl, returning them separately
+func stripExtensions(sdl string) (string, sdlExtensions) {
+	ext := sdlExtensions{
+		repeatable: map[string]bool{},
+		interfaces: map[string][]string{},
+	}
+	tokens := scanGraphQL(sdl)
+	src := []byte(sdl)
+	strip := func(start, end int) {
+		for i := start; i < end; i++ {
+			if src[i] != '\n' {
+				src[i] = ' '
+			}
+		}
+	}
+	next := func(i int) string {
+		if i+1 < len(tokens) {
+			return tokens[i+1].value
+		}
+		return ""
+	}
+	depth := 0
+	directive := ""
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		switch t.value {
+		case "{", "(":
+			depth++
+		case "}", ")":
+			depth--
+		}
+		if depth != 0 {
+			continue
+		}
+		switch {
+		case t.value == "directive" && next(i) == "@" && i+2 < len(tokens):
+			directive = tokens[i+2].value
+		case t.value == "repeatable" && directive != "" && next(i) == "on":
+			ext.repeatable[directive] = true
+			strip(t.start, t.end)
+		case t.value == "on":
+			directive = ""
+		case t.value == "interface" && i+2 < len(tokens) && tokens[i+2].value == "implements":
+			name := tokens[i+1].value
+			start, end := tokens[i+2].start, tokens[i+2].end
+			last := i + 2
+			if next(last) == "&" {
+				last++
+			}
+			for last+1 < len(tokens) && isNameStart(tokens[last+1].value[0]) {
+				last++
+				ext.interfaces[name] = append(ext.interfaces[name], tokens[last].value)
+				end = tokens[last].end
+				if next(last) != "&" {
+					break
+				}
+				last++
+			}
+			strip(start, end)
+			i = last
+		}
+	}
+	return string(src), ext
+}
+
+// definitionName returns name of type or directive defined
+// by def, directive names are prefixed with @
+func definitionName(def ast.Node) string {
+	switch d := def.(type) {
+	case *ast.DirectiveDefinition:
+		return "@" + d.Name.Value
+	case *ast.TypeExtensionDefinition:
+		return ""
+	case interface{ GetName() *ast.Name }:
+		return d.GetName().Value
+	}
+	return ""
+}
+
+// sdlBuilder builds introspection schema from SDL document
+type sdlBuilder struct {
+	ext        sdlExtensions
+	kinds      map[string]string
+	types      []*Type
+	byName     map[string]*Type
+	directives []Directive
+}
+
+func description(s *ast.StringValue) string {
+	if s == nil {
+		return ""
+	}
+	return s.Value
+}
+
+func directiveArg(directives []*ast.Directive, name, arg string) (*ast.Directive, string) {
+	for _, d := range directives {
+		if d.Name == nil || d.Name.Value != name {
+			continue
+		}
+		for _, a := range d.Arguments {
+			if a.Name != nil && a.Name.Value == arg {
+				if s, ok := a.Value.(*ast.StringValue); ok {
+					return d, s.Value
+				}
+			}
+		}
+		return d, ""
+	}
+	return nil, ""
+}
+
+func deprecation(directives []*ast.Directive) (bool, string) {
+	d, reason := directiveArg(directives, "deprecated", "reason")
+	if d == nil {
+		return false, ""
+	}
+	if reason == "" {
+		reason = DefaultDeprecationReason
+	}
+	return true, reason
+}
+
+func (b *sdlBuilder) typeRef(t ast.Type) (Type, error) {
+	switch tt := t.(type) {
+	case *ast.NonNull:
+		of, err := b.typeRef(tt.Type)
+		if err != nil {
+			return Type{}, err
+		}
+		return Type{Kind: graphql.TypeKindNonNull, OfType: &of}, nil
+	case *ast.List:
+		of, err := b.typeRef(tt.Type)
+		if err != nil {
+			return Type{}, err
+		}
+		return Type{Kind: graphql.TypeKindList, OfType: &of}, nil
+	case *ast.Named:
+		return b.named(tt.Name.Value)
+	}
+	return Type{}, fmt.Errorf("unsupported type %v", t)
+}
+
+func (b *sdlBuilder) named(name string) (Type, error) {
+	kind, ok := b.kinds[name]
+	if !ok {
+		return Type{}, fmt.Errorf("unknown type %s", name)
+	}
+	return Type{Kind: kind, Name: name}, nil
+}
+
+func (b *sdlBuilder) inputValues(defs []*ast.InputValueDefinition) ([]Arg, error) {
+	var args []Arg
+	for _, def := range defs {
+		t, err := b.typeRef(def.Type)
+		if err != nil {
+			return nil, err
+		}
+		a := Arg{
+			Name:        def.Name.Value,
+			Description: description(def.Description),
+			Type:        t,
+		}
+		if def.DefaultValue != nil {
+			a.DefaultValue = fmt.Sprint(printer.Print(def.DefaultValue))
+		}
+		a.IsDeprecated, a.DeprecationReason = deprecation(def.Directives)
+		args = append(args, a)
+	}
+	return args, nil
+}
+
+func (b *sdlBuilder) fields(defs []*ast.FieldDefinition) ([]Field, error) {
+	var fields []Field
+	for _, def := range defs {
+		t, err := b.typeRef(def.Type)
+		if err != nil {
+			return nil, err
+		}
+		args, err := b.inputValues(def.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		f := Field{
+			Name:        def.Name.Value,
+			Description: description(def.Description),
+			Type:        t,
+			Args:        args,
+		}
+		f.IsDeprecated, f.DeprecationReason = deprecation(def.Directives)
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+func (b *sdlBuilder) refs(names []*ast.Named) ([]Type, error) {
+	var refs []Type
+	for _, n := range names {
+		t, err := b.named(n.Name.Value)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, t)
+	}
+	return refs, nil
+}
+
+// declare registers kind of a type defined by def
+func (b *sdlBuilder) declare(def ast.Node) error {
+	var kind string
+	switch def.(type) {
+	case *ast.ScalarDefinition:
+		kind = graphql.TypeKindScalar
+	case *ast.ObjectDefinition:
+		kind = graphql.TypeKindObject
+	case *ast.InterfaceDefinition:
+		kind = graphql.TypeKindInterface
+	case *ast.UnionDefinition:
+		kind = graphql.TypeKindUnion
+	case *ast.EnumDefinition:
+		kind = graphql.TypeKindEnum
+	case *ast.InputObjectDefinition:
+		kind = graphql.TypeKindInputObject
+	default:
+		return nil
+	}
+	name := definitionName(def)
+	if _, ok := b.kinds[name]; ok {
+		return fmt.Errorf("type %s defined more than once", name)
+	}
+	b.kinds[name] = kind
+	t := &Type{Kind: kind, Name: name}
+	b.types = append(b.types, t)
+	b.byName[name] = t
+	return nil
+}
+
+// define fills type or directive defined by def
+func (b *sdlBuilder) define(def ast.Node) (err error) {
+	switch d := def.(type) {
+	case *ast.ScalarDefinition:
+		t := b.byName[d.Name.Value]
+		t.Description = description(d.Description)
+		_, t.SpecifiedByURL = directiveArg(d.Directives, "specifiedBy", "url")
+	case *ast.ObjectDefinition:
+		t := b.byName[d.Name.Value]
+		t.Description = description(d.Description)
+		if t.Fields, err = b.fields(d.Fields); err != nil {
+			return err
+		}
+		t.Interfaces, err = b.refs(d.Interfaces)
+	case *ast.InterfaceDefinition:
+		t := b.byName[d.Name.Value]
+		t.Description = description(d.Description)
+		if t.Fields, err = b.fields(d.Fields); err != nil {
+			return err
+		}
+		for _, name := range b.ext.interfaces[t.Name] {
+			i, err := b.named(name)
+			if err != nil {
+				return err
+			}
+			t.Interfaces = append(t.Interfaces, i)
+		}
+	case *ast.UnionDefinition:
+		t := b.byName[d.Name.Value]
+		t.Description = description(d.Description)
+		t.PossibleTypes, err = b.refs(d.Types)
+	case *ast.EnumDefinition:
+		t := b.byName[d.Name.Value]
+		t.Description = description(d.Description)
+		for _, v := range d.Values {
+			ev := EnumValue{
+				Name:        v.Name.Value,
+				Description: description(v.Description),
+			}
+			ev.IsDeprecated, ev.DeprecationReason = deprecation(v.Directives)
+			t.EnumValues = append(t.EnumValues, ev)
+		}
+	case *ast.InputObjectDefinition:
+		t := b.byName[d.Name.Value]
+		t.Description = description(d.Description)
+		if oneOf, _ := directiveArg(d.Directives, "oneOf", ""); oneOf != nil {
+			t.IsOneOf = true
+		}
+		t.InputFields, err = b.inputValues(d.Fields)
+	case *ast.DirectiveDefinition:
+		dir := Directive{
+			Name:         d.Name.Value,
+			Description:  description(d.Description),
+			IsRepeatable: b.ext.repeatable[d.Name.Value],
+		}
+		for _, l := range d.Locations {
+			dir.Locations = append(dir.Locations, l.Value)
+		}
+		if dir.Args, err = b.inputValues(d.Arguments); err != nil {
+			return err
+		}
+		b.directives = append(b.directives, dir)
+	}
+	return err
+}
+
+// extend adds fields and interfaces of type extension
+func (b *sdlBuilder) extend(def *ast.TypeExtensionDefinition) error {
+	name := def.Definition.Name.Value
+	t, ok := b.byName[name]
+	if !ok || !t.Object() {
+		return fmt.Errorf("cannot extend unknown type %s", name)
+	}
+	fields, err := b.fields(def.Definition.Fields)
+	if err != nil {
+		return err
+	}
+	interfaces, err := b.refs(def.Definition.Interfaces)
+	if err != nil {
+		return err
+	}
+	t.Fields = append(t.Fields, fields...)
+	t.Interfaces = append(t.Interfaces, interfaces...)
+	return nil
+}
+
+// possibleTypes sets objects implementing each of interfaces
+func (b *sdlBuilder) possibleTypes() {
+	for _, t := range b.types {
+		if !t.Object() {
+			continue
+		}
+		for _, i := range t.Interfaces {
+			if it := b.byName[i.Name]; it != nil && it.Interface() {
+				it.PossibleTypes = append(it.PossibleTypes, Type{Kind: t.Kind, Name: t.Name})
+			}
+		}
+	}
+}
+
+func (b *sdlBuilder) rootType(defs []ast.Node, op, def string) (Type, error) {
+	for _, d := range defs {
+		sd, ok := d.(*ast.SchemaDefinition)
+		if !ok {
+			continue
+		}
+		for _, ot := range sd.OperationTypes {
+			if ot.Operation == op {
+				if _, err := b.named(ot.Type.Name.Value); err != nil {
+					return Type{}, err
+				}
+				return Type{Name: ot.Type.Name.Value}, nil
+			}
+		}
+		return Type{}, nil
+	}
+	if t, ok := b.byName[def]; ok && t.Object() {
+		return Type{Name: def}, nil
+	}
+	return Type{}, nil
+}
+
+func parseDefinitions(sdl string) ([]ast.Node, sdlExtensions, error) {
+	src, ext := stripExtensions(sdl)
+	doc, err := parser.Parse(parser.ParseParams{Source: src})
+	if err != nil {
+		return nil, ext, err
+	}
+	return doc.Definitions, ext, nil
+}
+
+// ParseSDL builds schema from GraphQL schema definition language,
+// as if it was returned by introspection of a server using it.
+// Builtin scalars and directives are added, unless sdl
+// redefines them.
+func ParseSDL(sdl string) (Schema, error) {
+	defs, ext, err := parseDefinitions(sdl)
+	if err != nil {
+		return Schema{}, err
+	}
+	builtins, _, err := parseDefinitions(builtinSDL)
+	if err != nil {
+		// builtin definitions are always valid
+		panic(err)
+	}
+	defined := map[string]bool{}
+	for _, def := range defs {
+		defined[definitionName(def)] = true
+	}
+	for _, def := range builtins {
+		if !defined[definitionName(def)] {
+			defs = append(defs, def)
+		}
+	}
+	b := &sdlBuilder{
+		ext:    ext,
+		kinds:  map[string]string{},
+		byName: map[string]*Type{},
+	}
+	for _, def := range defs {
+		if err := b.declare(def); err != nil {
+			return Schema{}, err
+		}
+	}
+	for _, def := range defs {
+		if err := b.define(def); err != nil {
+			return Schema{}, err
+		}
+	}
+	for _, def := range defs {
+		if te, ok := def.(*ast.TypeExtensionDefinition); ok {
+			if err := b.extend(te); err != nil {
+				return Schema{}, err
+			}
+		}
+	}
+	b.possibleTypes()
+	var schema Schema
+	if schema.QueryType, err = b.rootType(defs, "query", "Query"); err != nil {
+		return Schema{}, err
+	}
+	if schema.QueryType.Name == "" {
+		return Schema{}, errors.New("schema has no query type")
+	}
+	if schema.MutationType, err = b.rootType(defs, "mutation", "Mutation"); err != nil {
+		return Schema{}, err
+	}
+	if schema.SubscriptionType, err = b.rootType(defs, "subscription", "Subscription"); err != nil {
+		return Schema{}, err
+	}
+	for _, t := range b.types {
+		schema.Types = append(schema.Types, *t)
+	}
+	schema.Directives = b.directives
+	return schema, nil
+}
+
+// ParseJSON builds schema from result of introspection query.
+// It accepts a whole GraphQL response, its data or just
+// __schema object.
+func ParseJSON(b []byte) (Schema, error) {
+	var out struct {
+		Data *struct {
+			Schema *Schema `json:"__schema"`
+		} `json:"data"`
+		Schema *Schema `json:"__schema"`
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return Schema{}, err
+	}
+	switch {
+	case out.Data != nil && out.Data.Schema != nil:
+		return *out.Data.Schema, nil
+	case out.Schema != nil:
+		return *out.Schema, nil
+	}
+	var schema Schema
+	if err := json.Unmarshal(b, &schema); err != nil {
+		return Schema{}, err
+	}
+	if schema.QueryType.Name == "" {
+		return Schema{}, errors.New("not an introspection result")
+	}
+	return schema, nil
+}
+
+// Parse builds schema either from introspection query
+// result in JSON or from schema definition language
+func Parse(b []byte) (Schema, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte("{")) {
+		return ParseJSON(b)
+	}
+	return ParseSDL(string(b))
+}
+
+// FromGraphQLSchema runs introspection query against schema
+// without the need for remote endpoint
+func FromGraphQLSchema(s *graphql.Schema) (Schema, error) {
+	res := graphql.Do(graphql.Params{
+		Schema:        *s,
+		RequestString: schemaInfoQuery,
+	})
+	if res.HasErrors() {
+		return Schema{}, fmt.Errorf("%v", res.Errors)
+	}
+	b, err := json.Marshal(res.Data)
+	if err != nil {
+		return Schema{}, err
+	}
+	return ParseJSON(b)
+}
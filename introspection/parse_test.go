@@ -0,0 +1,223 @@
+package introspection
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aexol/test_util"
+)
+
+const parseSDLSchema = `schema {
+  query: Root
+  mutation: Mutations
+}
+
+directive @tag(name: String!) repeatable on FIELD | OBJECT
+
+"""
+A "book"
+"""
+type Book implements Node & Named {
+  id: ID!
+  name: String
+}
+
+input By @oneOf {
+  id: ID
+
+  """color"""
+  color: Color = RED
+  tags: [String!] = ["a", "b"]
+}
+
+enum Color {
+  RED
+  BLUE @deprecated
+}
+
+scalar Date @specifiedBy(url: "https://example.com/date")
+
+type Mutations {
+  add(by: By!): Book
+}
+
+interface Named implements Node {
+  id: ID!
+  name: String
+}
+
+interface Node {
+  id: ID!
+}
+
+union Result = Book | Root
+
+type Root {
+  node(id: ID!): Node
+
+  """
+  Search
+  anything
+  """
+  search(
+    """text to find"""
+    text: String
+    limit: Int = 10 @deprecated
+  ): Result
+  old: String @deprecated(reason: "use \"new\"")
+}
+`
+
+func TestParseSDL(t *testing.T) {
+	assert := assert.New(t)
+	schema, err := ParseSDL(parseSDLSchema)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(parseSDLSchema, schema.SDL())
+	assert.Equal("Root", schema.QueryType.Name)
+	assert.Equal("Mutations", schema.MutationType.Name)
+	assert.Equal("", schema.SubscriptionType.Name)
+	node := Type{Name: "Node"}.Deref(schema.Types)
+	assert.Equal([]Type{named("OBJECT", "Book")}, node.PossibleTypes)
+	date := Type{Name: "Date"}.Deref(schema.Types)
+	assert.Equal("https://example.com/date", date.SpecifiedByURL)
+	assert.Equal("SCALAR", Type{Name: "String"}.Deref(schema.Types).Kind)
+	names := map[string]bool{}
+	for _, d := range schema.Directives {
+		names[d.Name] = true
+	}
+	for name := range builtinDirectives {
+		assert.True(names[name], name)
+	}
+	tp, ok := schema.TypeForPath([]string{"mutation", "add"})
+	assert.True(ok)
+	assert.Equal("Book", tp.Name)
+}
+
+type testCaseParseSDLError struct {
+	sdl string
+	err func(*assert.Assertions) test_util.ErrorAssertion
+}
+
+func (tt testCaseParseSDLError) test(t *testing.T) {
+	assert := assert.New(t)
+	if tt.err == nil {
+		tt.err = test_util.NoError
+	}
+	schema, err := ParseSDL(tt.sdl)
+	if tt.err(assert)(err) && err == nil {
+		assert.NotEmpty(schema.Types)
+	}
+}
+
+func TestParseSDLErrors(t *testing.T) {
+	tests := map[string]testCaseParseSDLError{
+		"DefaultRoot": {
+			sdl: "type Query { a: String }\ntype Mutation { b: String }",
+		},
+		"Extension": {
+			sdl: "type Query { a: String }\nextend type Query { b: Int }",
+		},
+		"RedefinedBuiltin": {
+			sdl: "scalar String\ndirective @deprecated(reason: String) on FIELD_DEFINITION\ntype Query { a: String }",
+		},
+		"NoQuery": {
+			sdl: "type Root { a: String }",
+			err: test_util.Error,
+		},
+		"UnknownType": {
+			sdl: "type Query { a: Missing }",
+			err: test_util.Error,
+		},
+		"Duplicate": {
+			sdl: "type Query { a: String }\ntype Query { b: String }",
+			err: test_util.Error,
+		},
+		"ExtendUnknown": {
+			sdl: "type Query { a: String }\nextend type Missing { b: Int }",
+			err: test_util.Error,
+		},
+		"Syntax": {
+			sdl: "type Query {",
+			err: test_util.Error,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.test)
+	}
+}
+
+type testCaseParse struct {
+	in  string
+	err func(*assert.Assertions) test_util.ErrorAssertion
+}
+
+func (tt testCaseParse) test(t *testing.T) {
+	assert := assert.New(t)
+	if tt.err == nil {
+		tt.err = test_util.NoError
+	}
+	schema, err := Parse([]byte(tt.in))
+	if tt.err(assert)(err) && err == nil {
+		assert.Equal("Query", schema.QueryType.Name)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]testCaseParse{
+		"SDL": {
+			in: "type Query { a: String }",
+		},
+		"Response": {
+			in: `{"data": {"__schema": {"queryType": {"name": "Query"}}}}`,
+		},
+		"Data": {
+			in: `{"__schema": {"queryType": {"name": "Query"}}}`,
+		},
+		"Schema": {
+			in: ` {"queryType": {"name": "Query"}}`,
+		},
+		"NotSchema": {
+			in:  `{"data": {}}`,
+			err: test_util.Error,
+		},
+		"InvalidJSON": {
+			in:  `{"data"`,
+			err: test_util.Error,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.test)
+	}
+}
+
+func TestFromGraphQLSchema(t *testing.T) {
+	assert := assert.New(t)
+	s, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"hello": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						"name": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+				},
+			},
+		}),
+	})
+	if !assert.NoError(err) {
+		return
+	}
+	schema, err := FromGraphQLSchema(&s)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal("Query", schema.QueryType.Name)
+	f, ok := schema.FieldForPath([]string{"query", "hello"})
+	assert.True(ok)
+	assert.Equal("hello(name: String!): String", f.GoString())
+}
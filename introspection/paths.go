@@ -0,0 +1,189 @@
+package introspection
+
+import (
+	"strings"
+)
+
+// PathStep is a field on resolve path
+type PathStep struct {
+	// Field name
+	Field string `json:"field"`
+	// Required arguments of field, that is non null
+	// arguments without default value
+	Required []Arg `json:"required,omitempty"`
+	// On is a member of union or implementation of interface
+	// returned by field, that path continues with
+	On string `json:"on,omitempty"`
+}
+
+// ResolvePath is a path of fields from root operation
+// to a field returning some type
+type ResolvePath struct {
+	// Operation is one of query, mutation or subscription
+	Operation string `json:"operation"`
+	// Steps are fields resolved on path
+	Steps []PathStep `json:"steps"`
+}
+
+// String formats path as space separated list of operation
+// and field names
+func (p ResolvePath) String() string {
+	parts := []string{p.Operation}
+	for _, s := range p.Steps {
+		parts = append(parts, s.Field)
+		if s.On != "" {
+			parts = append(parts, "... on "+s.On)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// reachable finds types from which target can be reached over
+// exactly k field edges, for each k up to maxDepth. Unions and
+// interfaces reach target if any of their possible types does.
+func (s Schema) reachable(target string, maxDepth int) []map[string]bool {
+	// reverse edges, from field type to types defining field
+	parents := map[string][]string{}
+	// reverse edges, from possible type to abstract types
+	abstract := map[string][]string{}
+	for _, t := range s.Types {
+		for _, f := range t.Fields {
+			leaf := f.Type.GetOfTypeLeaf().Name
+			parents[leaf] = append(parents[leaf], t.Name)
+		}
+		for _, p := range t.PossibleTypes {
+			abstract[p.Name] = append(abstract[p.Name], t.Name)
+		}
+	}
+	withAbstract := func(level map[string]bool) map[string]bool {
+		for name := range level {
+			for _, a := range abstract[name] {
+				level[a] = true
+			}
+		}
+		return level
+	}
+	reach := []map[string]bool{withAbstract(map[string]bool{target: true})}
+	for k := 1; k <= maxDepth; k++ {
+		level := map[string]bool{}
+		for name := range reach[k-1] {
+			for _, p := range parents[name] {
+				level[p] = true
+			}
+		}
+		reach = append(reach, withAbstract(level))
+	}
+	return reach
+}
+
+// PathsTo finds up to limit shortest resolve paths from root
+// operations to fields returning type named target. Paths never
+// visit the same type twice, so cycles in schema are not followed.
+// Fields returning unions or interfaces can continue with one of
+// their possible types, set as On of the step.
+// Paths longer than maxDepth fields are not searched, as number
+// of paths grows exponentially with their length on schemas with
+// many links between types. Paths are ordered by length, then by
+// root operation and field definition order.
+func (s Schema) PathsTo(target string, limit, maxDepth int) []ResolvePath {
+	var paths []ResolvePath
+	// simple path is never longer than number of types
+	if maxDepth > len(s.Types) {
+		maxDepth = len(s.Types)
+	}
+	reach := s.reachable(target, maxDepth)
+	types := make(map[string]Type, len(s.Types))
+	for _, t := range s.Types {
+		types[t.Name] = t
+	}
+	roots := []struct {
+		op string
+		t  Type
+	}{
+		{"query", s.QueryType},
+		{"mutation", s.MutationType},
+		{"subscription", s.SubscriptionType},
+	}
+	full := func() bool {
+		return len(paths) >= limit
+	}
+	var steps []PathStep
+	visited := map[string]bool{}
+	add := func(op string) {
+		paths = append(paths, ResolvePath{
+			Operation: op,
+			Steps:     append([]PathStep{}, steps...),
+		})
+	}
+	// walk collects simple paths of exactly length
+	// steps, following only fields of types that can
+	// reach target in exactly remaining steps
+	var walk func(op string, t Type, length int)
+	walk = func(op string, t Type, length int) {
+		if full() {
+			return
+		}
+		// possible types of type returned by last field
+		var possible []Type
+		if len(steps) != 0 {
+			for _, p := range t.PossibleTypes {
+				if pt, ok := types[p.Name]; ok && !visited[p.Name] && pt.Name != t.Name {
+					possible = append(possible, pt)
+				}
+			}
+		}
+		last := len(steps) - 1
+		if len(steps) == length {
+			if t.Name == target {
+				add(op)
+				return
+			}
+			for _, p := range possible {
+				if p.Name == target {
+					steps[last].On = p.Name
+					add(op)
+					steps[last].On = ""
+				}
+			}
+			return
+		}
+		visited[t.Name] = true
+		defer delete(visited, t.Name)
+		for _, from := range append([]Type{t}, possible...) {
+			if from.Name != t.Name {
+				visited[from.Name] = true
+				steps[last].On = from.Name
+			}
+			for _, f := range from.Fields {
+				next := f.Type.GetOfTypeLeaf().Name
+				if visited[next] || !reach[length-len(steps)-1][next] {
+					continue
+				}
+				step := PathStep{Field: f.Name}
+				for _, a := range f.Args {
+					if required(a) {
+						step.Required = append(step.Required, a)
+					}
+				}
+				steps = append(steps, step)
+				walk(op, types[next], length)
+				steps = steps[:len(steps)-1]
+				if full() {
+					break
+				}
+			}
+			if from.Name != t.Name {
+				delete(visited, from.Name)
+				steps[last].On = ""
+			}
+		}
+	}
+	for length := 0; length <= maxDepth && !full(); length++ {
+		for _, r := range roots {
+			if r.t.Name != "" && reach[length][r.t.Name] {
+				walk(r.op, types[r.t.Name], length)
+			}
+		}
+	}
+	return paths
+}
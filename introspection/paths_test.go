@@ -0,0 +1,174 @@
+package introspection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const pathsSchema = `
+type Query {
+  viewer: User
+  repository(owner: String!, name: String!, ref: String = "main"): Repository
+  node(id: ID!): Node
+}
+
+type Mutation {
+  createIssue(title: String!, body: String): Issue
+}
+
+interface Node {
+  id: ID!
+}
+
+type User implements Node {
+  id: ID!
+  repositories(first: Int!): [Repository!]!
+  friend: User
+}
+
+type Repository implements Node {
+  id: ID!
+  owner: User
+  issues: [Issue]
+  issue(number: Int!): Issue
+}
+
+type Issue implements Node {
+  id: ID!
+  author: User
+  repository: Repository
+}
+`
+
+const unionPathsSchema = `
+type Query {
+  search(text: String!): [Result]
+}
+
+union Result = Issue | Label
+
+type Issue {
+  labels: [Label]
+}
+
+type Label {
+  name: String
+}
+`
+
+type testCasePaths struct {
+	schema   string
+	target   string
+	limit    int
+	maxDepth int
+	paths    []string
+}
+
+func (tt testCasePaths) test(t *testing.T) {
+	assert := assert.New(t)
+	if tt.schema == "" {
+		tt.schema = pathsSchema
+	}
+	s, err := ParseSDL(tt.schema)
+	if !assert.NoError(err) {
+		return
+	}
+	if tt.maxDepth == 0 {
+		tt.maxDepth = 10
+	}
+	var paths []string
+	for _, p := range s.PathsTo(tt.target, tt.limit, tt.maxDepth) {
+		paths = append(paths, p.String())
+	}
+	assert.Equal(tt.paths, paths)
+}
+
+func TestSchemaPathsTo(t *testing.T) {
+	tests := map[string]testCasePaths{
+		"Issue": {
+			target: "Issue",
+			limit:  5,
+			paths: []string{
+				"query node ... on Issue",
+				"mutation createIssue",
+				"query repository issues",
+				"query repository issue",
+				"query node ... on Repository issues",
+			},
+		},
+		"Cycles": {
+			target: "User",
+			limit:  10,
+			paths: []string{
+				"query viewer",
+				"query node ... on User",
+				"query repository owner",
+				"query node ... on Repository owner",
+				"query node ... on Issue author",
+				"mutation createIssue author",
+				"query repository issues author",
+				"query repository issue author",
+				"query node ... on Repository issues author",
+				"query node ... on Repository issue author",
+			},
+		},
+		"MaxDepth": {
+			target:   "User",
+			limit:    10,
+			maxDepth: 2,
+			paths: []string{
+				"query viewer",
+				"query node ... on User",
+				"query repository owner",
+				"query node ... on Repository owner",
+				"query node ... on Issue author",
+				"mutation createIssue author",
+			},
+		},
+		"Union": {
+			schema: unionPathsSchema,
+			target: "Label",
+			limit:  10,
+			paths: []string{
+				"query search ... on Label",
+				"query search ... on Issue labels",
+			},
+		},
+		"Root": {
+			target: "Query",
+			limit:  1,
+			paths:  []string{"query"},
+		},
+		"Unreachable": {
+			target: "Missing",
+			limit:  1,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.test)
+	}
+}
+
+func TestSchemaPathsToRequiredArgs(t *testing.T) {
+	assert := assert.New(t)
+	s, err := ParseSDL(pathsSchema)
+	if !assert.NoError(err) {
+		return
+	}
+	paths := s.PathsTo("Issue", 4, 10)
+	if !assert.Len(paths, 4) {
+		return
+	}
+	var required []string
+	for _, a := range paths[3].Steps[0].Required {
+		required = append(required, a.Name)
+	}
+	assert.Equal([]string{"owner", "name"}, required)
+	assert.Equal("number", paths[3].Steps[1].Required[0].Name)
+	assert.Len(paths[1].Steps[0].Required, 1)
+	assert.Empty(paths[2].Steps[1].Required)
+	// node returns interface narrowed to Issue
+	assert.Equal("id", paths[0].Steps[0].Required[0].Name)
+	assert.Equal("Issue", paths[0].Steps[0].On)
+}
@@ -0,0 +1,306 @@
+package introspection
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultDeprecationReason is a reason of deprecation
+// assumed if @deprecated directive has no reason
+const DefaultDeprecationReason = "No longer supported"
+
+var (
+	builtinScalars = map[string]bool{
+		"String":  true,
+		"Int":     true,
+		"Float":   true,
+		"Boolean": true,
+		"ID":      true,
+	}
+	builtinDirectives = map[string]bool{
+		"skip":        true,
+		"include":     true,
+		"deprecated":  true,
+		"specifiedBy": true,
+		"oneOf":       true,
+	}
+)
+
+// Builtin returns true if type is defined by GraphQL
+// specification, as in, is a scalar like String or
+// introspection type
+func (t Type) Builtin() bool {
+	return strings.HasPrefix(t.Name, "__") || (t.Scalar() && builtinScalars[t.Name])
+}
+
+// Builtin returns true if directive is defined by
+// GraphQL specification
+func (d Directive) Builtin() bool {
+	return builtinDirectives[d.Name]
+}
+
+// quote formats s as GraphQL string
+func quote(s string) string {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		// string is always encodable
+		panic(err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// blockString formats s as GraphQL block string
+// indented by indent
+func blockString(s, indent string) string {
+	s = strings.Replace(s, `"""`, `\"""`, -1)
+	if !strings.Contains(s, "\n") && !strings.HasSuffix(s, `"`) && !strings.HasSuffix(s, `\`) {
+		return indent + `"""` + s + `"""`
+	}
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = indent + l
+		}
+	}
+	return indent + `"""` + "\n" + strings.Join(lines, "\n") + "\n" + indent + `"""`
+}
+
+type sdlPrinter struct {
+	bytes.Buffer
+}
+
+func (p *sdlPrinter) description(desc, indent string, first bool) {
+	if desc == "" {
+		return
+	}
+	if indent != "" && !first {
+		p.WriteString("\n")
+	}
+	p.WriteString(blockString(desc, indent))
+	p.WriteString("\n")
+}
+
+func deprecated(isDeprecated bool, reason string) string {
+	if !isDeprecated {
+		return ""
+	}
+	if reason == "" || reason == DefaultDeprecationReason {
+		return " @deprecated"
+	}
+	return " @deprecated(reason: " + quote(reason) + ")"
+}
+
+func (p *sdlPrinter) inputValue(a Arg, indent string) {
+	p.WriteString(indent + a.Name + ": " + a.Type.GoString())
+	if a.DefaultValue != "" {
+		p.WriteString(" = " + a.DefaultValue)
+	}
+	p.WriteString(deprecated(a.IsDeprecated, a.DeprecationReason))
+}
+
+func (p *sdlPrinter) args(args []Arg, indent string) {
+	if len(args) == 0 {
+		return
+	}
+	multiline := false
+	for _, a := range args {
+		if a.Description != "" {
+			multiline = true
+			break
+		}
+	}
+	if !multiline {
+		p.WriteString("(")
+		for i, a := range args {
+			if i != 0 {
+				p.WriteString(", ")
+			}
+			p.inputValue(a, "")
+		}
+		p.WriteString(")")
+		return
+	}
+	p.WriteString("(\n")
+	for i, a := range args {
+		p.description(a.Description, indent+"  ", i == 0)
+		p.inputValue(a, indent+"  ")
+		p.WriteString("\n")
+	}
+	p.WriteString(indent + ")")
+}
+
+func (p *sdlPrinter) fields(fields []Field) {
+	if len(fields) == 0 {
+		return
+	}
+	p.WriteString(" {\n")
+	for i, f := range fields {
+		p.description(f.Description, "  ", i == 0)
+		p.WriteString("  " + f.Name)
+		p.args(f.Args, "  ")
+		p.WriteString(": " + f.Type.GoString())
+		p.WriteString(deprecated(f.IsDeprecated, f.DeprecationReason))
+		p.WriteString("\n")
+	}
+	p.WriteString("}")
+}
+
+func (p *sdlPrinter) interfaces(t Type) {
+	if len(t.Interfaces) == 0 {
+		return
+	}
+	names := make([]string, 0, len(t.Interfaces))
+	for _, i := range t.Interfaces {
+		names = append(names, i.Name)
+	}
+	p.WriteString(" implements " + strings.Join(names, " & "))
+}
+
+func (p *sdlPrinter) typeDef(t Type) {
+	p.description(t.Description, "", true)
+	switch {
+	case t.Scalar():
+		p.WriteString("scalar " + t.Name)
+		if t.SpecifiedByURL != "" {
+			p.WriteString(" @specifiedBy(url: " + quote(t.SpecifiedByURL) + ")")
+		}
+	case t.Object():
+		p.WriteString("type " + t.Name)
+		p.interfaces(t)
+		p.fields(t.Fields)
+	case t.Interface():
+		p.WriteString("interface " + t.Name)
+		p.interfaces(t)
+		p.fields(t.Fields)
+	case t.Union():
+		p.WriteString("union " + t.Name)
+		if len(t.PossibleTypes) != 0 {
+			names := make([]string, 0, len(t.PossibleTypes))
+			for _, pt := range t.PossibleTypes {
+				names = append(names, pt.Name)
+			}
+			p.WriteString(" = " + strings.Join(names, " | "))
+		}
+	case t.Enum():
+		p.WriteString("enum " + t.Name)
+		if len(t.EnumValues) != 0 {
+			p.WriteString(" {\n")
+			for i, v := range t.EnumValues {
+				p.description(v.Description, "  ", i == 0)
+				p.WriteString("  " + v.Name)
+				p.WriteString(deprecated(v.IsDeprecated, v.DeprecationReason))
+				p.WriteString("\n")
+			}
+			p.WriteString("}")
+		}
+	case t.Input():
+		p.WriteString("input " + t.Name)
+		if t.IsOneOf {
+			p.WriteString(" @oneOf")
+		}
+		if len(t.InputFields) != 0 {
+			p.WriteString(" {\n")
+			for i, f := range t.InputFields {
+				p.description(f.Description, "  ", i == 0)
+				p.inputValue(f, "  ")
+				p.WriteString("\n")
+			}
+			p.WriteString("}")
+		}
+	}
+}
+
+func (p *sdlPrinter) directive(d Directive) {
+	p.description(d.Description, "", true)
+	p.WriteString("directive @" + d.Name)
+	p.args(d.Args, "")
+	if d.IsRepeatable {
+		p.WriteString(" repeatable")
+	}
+	p.WriteString(" on " + strings.Join(d.Locations, " | "))
+}
+
+// schemaDef returns schema definition, which is omitted
+// if root operation types use default names
+func (p *sdlPrinter) schemaDef(s Schema) bool {
+	roots := []struct {
+		op   string
+		name string
+		def  string
+	}{
+		{"query", s.QueryType.Name, "Query"},
+		{"mutation", s.MutationType.Name, "Mutation"},
+		{"subscription", s.SubscriptionType.Name, "Subscription"},
+	}
+	needed := s.Description != ""
+	for _, r := range roots {
+		if r.name != "" && r.name != r.def {
+			needed = true
+		}
+	}
+	if !needed {
+		return false
+	}
+	p.description(s.Description, "", true)
+	p.WriteString("schema {\n")
+	for _, r := range roots {
+		if r.name != "" {
+			fmt.Fprintf(p, "  %s: %s\n", r.op, r.name)
+		}
+	}
+	p.WriteString("}")
+	return true
+}
+
+// SDL formats schema as GraphQL schema definition language.
+// Types and directives defined by GraphQL specification are
+// omitted, other types and directives are sorted by name, so
+// that output is deterministic.
+func (s Schema) SDL() string {
+	p := &sdlPrinter{}
+	sep := false
+	next := func() {
+		if sep {
+			p.WriteString("\n\n")
+		}
+		sep = true
+	}
+	if p.schemaDef(s) {
+		sep = true
+	}
+	directives := make([]Directive, 0, len(s.Directives))
+	for _, d := range s.Directives {
+		if !d.Builtin() {
+			directives = append(directives, d)
+		}
+	}
+	sort.SliceStable(directives, func(i, j int) bool {
+		return directives[i].Name < directives[j].Name
+	})
+	for _, d := range directives {
+		next()
+		p.directive(d)
+	}
+	types := make([]Type, 0, len(s.Types))
+	for _, t := range s.Types {
+		if !t.Builtin() {
+			types = append(types, t)
+		}
+	}
+	sort.SliceStable(types, func(i, j int) bool {
+		return types[i].Name < types[j].Name
+	})
+	for _, t := range types {
+		next()
+		p.typeDef(t)
+	}
+	if sep {
+		p.WriteString("\n")
+	}
+	return p.String()
+}
@@ -0,0 +1,115 @@
+package introspection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func named(kind, name string) Type {
+	return Type{Kind: kind, Name: name}
+}
+
+func nonNull(t Type) Type {
+	return Type{Kind: "NON_NULL", OfType: &t}
+}
+
+func TestSchemaSDL(t *testing.T) {
+	assert := assert.New(t)
+	str := named("SCALAR", "String")
+	schema := Schema{
+		QueryType: Type{Name: "Root"},
+		Types: []Type{
+			{
+				Kind: "OBJECT",
+				Name: "Root",
+				Fields: []Field{
+					{Name: "node", Type: named("INTERFACE", "Node"), Args: []Arg{
+						{Name: "id", Type: nonNull(named("SCALAR", "ID"))},
+					}},
+					{Name: "search", Type: named("UNION", "Result"), Description: "Search\nanything", Args: []Arg{
+						{Name: "text", Type: str, Description: "text to find"},
+						{Name: "limit", Type: named("SCALAR", "Int"), DefaultValue: "10", IsDeprecated: true},
+					}},
+					{Name: "old", Type: str, IsDeprecated: true, DeprecationReason: "use \"new\""},
+				},
+			},
+			{Kind: "INTERFACE", Name: "Node", Fields: []Field{{Name: "id", Type: nonNull(named("SCALAR", "ID"))}}},
+			{
+				Kind:        "OBJECT",
+				Name:        "Book",
+				Description: `A "book"`,
+				Interfaces:  []Type{named("INTERFACE", "Node")},
+				Fields:      []Field{{Name: "id", Type: nonNull(named("SCALAR", "ID"))}},
+			},
+			{Kind: "UNION", Name: "Result", PossibleTypes: []Type{named("OBJECT", "Book"), named("OBJECT", "Root")}},
+			{Kind: "ENUM", Name: "Color", EnumValues: []EnumValue{
+				{Name: "RED"},
+				{Name: "BLUE", IsDeprecated: true, DeprecationReason: DefaultDeprecationReason},
+			}},
+			{Kind: "INPUT_OBJECT", Name: "By", IsOneOf: true, InputFields: []Arg{
+				{Name: "id", Type: named("SCALAR", "ID")},
+				{Name: "color", Type: named("ENUM", "Color"), DefaultValue: "RED", Description: "color"},
+			}},
+			{Kind: "SCALAR", Name: "Date", SpecifiedByURL: "https://example.com/date"},
+			str,
+			{Kind: "OBJECT", Name: "__Type"},
+		},
+		Directives: []Directive{
+			{Name: "skip", Locations: []string{"FIELD"}},
+			{Name: "tag", Locations: []string{"FIELD", "OBJECT"}, IsRepeatable: true, Args: []Arg{
+				{Name: "name", Type: nonNull(str)},
+			}},
+		},
+	}
+	expected := `schema {
+  query: Root
+}
+
+directive @tag(name: String!) repeatable on FIELD | OBJECT
+
+"""
+A "book"
+"""
+type Book implements Node {
+  id: ID!
+}
+
+input By @oneOf {
+  id: ID
+
+  """color"""
+  color: Color = RED
+}
+
+enum Color {
+  RED
+  BLUE @deprecated
+}
+
+scalar Date @specifiedBy(url: "https://example.com/date")
+
+interface Node {
+  id: ID!
+}
+
+union Result = Book | Root
+
+type Root {
+  node(id: ID!): Node
+
+  """
+  Search
+  anything
+  """
+  search(
+    """text to find"""
+    text: String
+    limit: Int = 10 @deprecated
+  ): Result
+  old: String @deprecated(reason: "use \"new\"")
+}
+`
+	assert.Equal(expected, schema.SDL())
+	assert.Equal("", Schema{}.SDL())
+}
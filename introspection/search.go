@@ -0,0 +1,190 @@
+package introspection
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/agnivade/levenshtein"
+)
+
+// SearchMode selects how search term is matched
+// against names and descriptions
+type SearchMode string
+
+const (
+	// SearchSubstring matches names and descriptions
+	// containing term, ignoring case
+	SearchSubstring SearchMode = "substring"
+	// SearchRegex matches names and descriptions
+	// against term as a regular expression
+	SearchRegex SearchMode = "regex"
+	// SearchFuzzy matches names and words of descriptions
+	// within two edits of term, ignoring case
+	SearchFuzzy SearchMode = "fuzzy"
+)
+
+// Kinds of schema elements found by Search
+const (
+	SearchType       = "type"
+	SearchField      = "field"
+	SearchArgument   = "argument"
+	SearchInputField = "input field"
+	SearchEnumValue  = "enum value"
+)
+
+// SearchResult is a schema element matching search term
+type SearchResult struct {
+	// Kind of element
+	Kind string `json:"kind"`
+	// Coordinate of element, such as Type.field(arg:)
+	Coordinate string `json:"coordinate"`
+	// Path is a resolve path to element, empty if element
+	// cannot be reached from any of root types
+	Path []string `json:"path,omitempty"`
+	// Type of element, for arguments it is a type of field
+	Type string `json:"type,omitempty"`
+}
+
+// String formats result as resolve path followed by type,
+// elements without resolve path are formatted as coordinate
+func (r SearchResult) String() string {
+	s := r.Coordinate
+	if len(r.Path) != 0 {
+		s = strings.Join(r.Path, " ")
+	}
+	if r.Type != "" {
+		s += " -> " + r.Type
+	}
+	return s
+}
+
+func searchMatcher(term string, mode SearchMode) (func(name, description string) bool, error) {
+	switch mode {
+	case SearchSubstring, "":
+		term = strings.ToLower(term)
+		return func(name, description string) bool {
+			return strings.Contains(strings.ToLower(name), term) ||
+				strings.Contains(strings.ToLower(description), term)
+		}, nil
+	case SearchRegex:
+		re, err := regexp.Compile(term)
+		if err != nil {
+			return nil, err
+		}
+		return func(name, description string) bool {
+			return re.MatchString(name) || re.MatchString(description)
+		}, nil
+	case SearchFuzzy:
+		term = strings.ToLower(term)
+		fuzzy := func(s string) bool {
+			// Only accept matches with distance of less
+			// than 3 edits
+			return levenshtein.ComputeDistance(term, strings.ToLower(s)) < 3
+		}
+		return func(name, description string) bool {
+			if fuzzy(name) {
+				return true
+			}
+			for _, w := range strings.FieldsFunc(description, func(r rune) bool {
+				return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+			}) {
+				if fuzzy(w) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown search mode %s", mode)
+}
+
+// resolvePaths finds shortest resolve path to each of
+// types reachable from root types
+func (s Schema) resolvePaths() map[string][]string {
+	paths := map[string][]string{}
+	var queue []string
+	for _, r := range []struct {
+		op string
+		t  Type
+	}{
+		{"query", s.QueryType},
+		{"mutation", s.MutationType},
+		{"subscription", s.SubscriptionType},
+	} {
+		if _, ok := paths[r.t.Name]; r.t.Name != "" && !ok {
+			paths[r.t.Name] = []string{r.op}
+			queue = append(queue, r.t.Name)
+		}
+	}
+	for len(queue) != 0 {
+		name := queue[0]
+		queue = queue[1:]
+		t := Type{Name: name}.Deref(s.Types)
+		for _, f := range t.Fields {
+			leaf := f.Type.GetOfTypeLeaf().Name
+			if _, ok := paths[leaf]; ok {
+				continue
+			}
+			path := make([]string, len(paths[name]), len(paths[name])+1)
+			copy(path, paths[name])
+			paths[leaf] = append(path, f.Name)
+			queue = append(queue, leaf)
+		}
+	}
+	return paths
+}
+
+// Search finds types, fields, arguments, input fields and enum
+// values with name or description matching term. Results are
+// ordered by type name and then by order of definition.
+func (s Schema) Search(term string, mode SearchMode) ([]SearchResult, error) {
+	match, err := searchMatcher(term, mode)
+	if err != nil {
+		return nil, err
+	}
+	paths := s.resolvePaths()
+	var results []SearchResult
+	// add appends result with resolve path of type t extended
+	// with segment, input fields and enum values are not part
+	// of any resolve path, so they are added without it
+	add := func(r SearchResult, t Type, segment string) {
+		if path, ok := paths[t.Name]; ok {
+			r.Path = append([]string{}, path...)
+			if segment != "" {
+				r.Path = append(r.Path, segment)
+			}
+		}
+		results = append(results, r)
+	}
+	types := typeMap(s.Types)
+	for _, name := range sortedKeys(types) {
+		t := types[name]
+		if match(t.Name, t.Description) {
+			add(SearchResult{Kind: SearchType, Coordinate: t.Name, Type: t.Name}, t, "")
+		}
+		for _, f := range t.Fields {
+			if match(f.Name, f.Description) {
+				add(SearchResult{Kind: SearchField, Coordinate: t.Name + "." + f.Name, Type: f.Type.GoString()}, t, f.Name)
+			}
+			for _, a := range f.Args {
+				if match(a.Name, a.Description) {
+					segment := f.Name + "(" + a.Name + ":)"
+					add(SearchResult{Kind: SearchArgument, Coordinate: t.Name + "." + segment, Type: f.Type.GoString()}, t, segment)
+				}
+			}
+		}
+		for _, f := range t.InputFields {
+			if match(f.Name, f.Description) {
+				results = append(results, SearchResult{Kind: SearchInputField, Coordinate: t.Name + "." + f.Name, Type: f.Type.GoString()})
+			}
+		}
+		for _, v := range t.EnumValues {
+			if match(v.Name, v.Description) {
+				results = append(results, SearchResult{Kind: SearchEnumValue, Coordinate: t.Name + "." + v.Name})
+			}
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,136 @@
+package introspection
+
+import (
+	"testing"
+
+	"github.com/aexol/test_util"
+	"github.com/stretchr/testify/assert"
+)
+
+const searchSchema = `
+type Query {
+  repository(owner: String!, name: String!): Repository
+  viewer: User
+}
+
+type Mutation {
+  closeIssue(id: ID!): Issue
+}
+
+"A repository containing issues"
+type Repository {
+  name: String
+  issues(first: Int, states: [IssueState!]): IssueConnection
+}
+
+type IssueConnection {
+  nodes: [Issue]
+}
+
+type Issue {
+  title: String
+  state: IssueState
+}
+
+type User {
+  login: String
+}
+
+type Orphan {
+  issue: Issue
+}
+
+enum IssueState {
+  OPEN
+  "Issue was closed"
+  CLOSED
+}
+
+input IssueFilter {
+  state: IssueState
+}
+`
+
+type testCaseSearch struct {
+	term    string
+	mode    SearchMode
+	results []string
+	err     func(*assert.Assertions) test_util.ErrorAssertion
+}
+
+func (tt testCaseSearch) test(t *testing.T) {
+	assert := assert.New(t)
+	if tt.err == nil {
+		tt.err = test_util.NoError
+	}
+	s, err := ParseSDL(searchSchema)
+	if !assert.NoError(err) {
+		return
+	}
+	results, err := s.Search(tt.term, tt.mode)
+	tt.err(assert)(err)
+	var out []string
+	for _, r := range results {
+		out = append(out, r.String())
+	}
+	assert.Equal(tt.results, out)
+}
+
+func TestSchemaSearch(t *testing.T) {
+	tests := map[string]testCaseSearch{
+		"Substring": {
+			term: "Issue",
+			mode: SearchSubstring,
+			results: []string{
+				"mutation closeIssue -> Issue",
+				"query repository issues -> IssueConnection",
+				"IssueFilter -> IssueFilter",
+				"mutation closeIssue state -> IssueState",
+				"IssueState.CLOSED",
+				"mutation closeIssue -> Issue",
+				"Orphan.issue -> Issue",
+				"query repository -> Repository",
+				"query repository issues -> IssueConnection",
+			},
+		},
+		"Argument": {
+			term: "first",
+			results: []string{
+				"query repository issues(first:) -> IssueConnection",
+			},
+		},
+		"Regex": {
+			term: "^(login|state)$",
+			mode: SearchRegex,
+			results: []string{
+				"mutation closeIssue state -> IssueState",
+				"IssueFilter.state -> IssueState",
+				"query viewer login -> String",
+			},
+		},
+		"Fuzzy": {
+			term: "isue",
+			mode: SearchFuzzy,
+			results: []string{
+				"mutation closeIssue -> Issue",
+				"IssueState.CLOSED",
+				"Orphan.issue -> Issue",
+				"query repository -> Repository",
+				"query repository issues -> IssueConnection",
+			},
+		},
+		"InvalidRegex": {
+			term: "(",
+			mode: SearchRegex,
+			err:  test_util.Error,
+		},
+		"UnknownMode": {
+			term: "a",
+			mode: "exact",
+			err:  test_util.Error,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.test)
+	}
+}
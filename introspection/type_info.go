@@ -4,6 +4,7 @@ package introspection
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 
@@ -19,8 +20,13 @@ type Arg struct {
 	// Description is a schema defined argument description
 	Description string `json:"description,omitempty"`
 	// Type is a type or type reference defined by schema
-	Type         Type   `json:"type,omitempty"`
+	Type Type `json:"type,omitempty"`
+	// DefaultValue is a GraphQL literal of argument default value
 	DefaultValue string `json:"defaultValue,omitempty"`
+	// IsDeprecated is true if argument or input field is deprecated
+	IsDeprecated bool `json:"isDeprecated,omitempty"`
+	// DeprecationReason is a schema defined reason of deprecation
+	DeprecationReason string `json:"deprecationReason,omitempty"`
 }
 
 // GoString formats argument as it would apear in schema
@@ -38,6 +44,10 @@ type Field struct {
 	Type Type `json:"type,omitempty"`
 	// Description is a schema defined argument description
 	Description string `json:"description,omitempty"`
+	// IsDeprecated is true if field is deprecated
+	IsDeprecated bool `json:"isDeprecated,omitempty"`
+	// DeprecationReason is a schema defined reason of deprecation
+	DeprecationReason string `json:"deprecationReason,omitempty"`
 }
 
 // ArgsString formats field arguments as they would apear in schema
@@ -91,6 +101,46 @@ type Type struct {
 	// OfType is a type reference which this type wraps
 	// only valid for NonNull and List type kinds
 	OfType *Type `json:"ofType,omitempty"`
+	// InputFields is a list of fields of Input type
+	InputFields []Arg `json:"inputFields,omitempty"`
+	// Interfaces is a list of interfaces implemented by
+	// Object or Interface type
+	Interfaces []Type `json:"interfaces,omitempty"`
+	// EnumValues is a list of values of Enum type
+	EnumValues []EnumValue `json:"enumValues,omitempty"`
+	// SpecifiedByURL is an url of scalar specification,
+	// only valid for Scalar kind
+	SpecifiedByURL string `json:"specifiedByURL,omitempty"`
+	// IsOneOf is true for Input type which requires
+	// exactly one field to be set
+	IsOneOf bool `json:"isOneOf,omitempty"`
+}
+
+// EnumValue is a value of graphql enum type
+type EnumValue struct {
+	// Name is an enum value as defined by schema
+	Name string `json:"name,omitempty"`
+	// Description is a schema defined value description
+	Description string `json:"description,omitempty"`
+	// IsDeprecated is true if value is deprecated
+	IsDeprecated bool `json:"isDeprecated,omitempty"`
+	// DeprecationReason is a schema defined reason of deprecation
+	DeprecationReason string `json:"deprecationReason,omitempty"`
+}
+
+// Directive is a graphql directive defined by schema
+type Directive struct {
+	// Name is a directive name, without @
+	Name string `json:"name,omitempty"`
+	// Description is a schema defined directive description
+	Description string `json:"description,omitempty"`
+	// Locations is a list of locations where directive can be used
+	Locations []string `json:"locations,omitempty"`
+	// Args is a list of directive arguments
+	Args []Arg `json:"args,omitempty"`
+	// IsRepeatable is true if directive can be used more
+	// than once at the same location
+	IsRepeatable bool `json:"isRepeatable,omitempty"`
 }
 
 // Named returns true if type is named, as in, not NonNull or List
@@ -155,10 +205,11 @@ func (t Type) GetOfTypeLeaf() Type {
 
 // TypeRef returns true if type is a type reference
 func (t Type) TypeRef() bool {
-	// Inputs/Interfaces/Objects without fields, Unions without possible
-	// types and types without kind but with name are assumed to be a
-	// reference
-	return ((t.Input() || t.Interface() || t.Object()) && len(t.Fields) == 0) ||
+	// Inputs without input fields, Interfaces/Objects without fields,
+	// Unions without possible types and types without kind but with
+	// name are assumed to be a reference
+	return (t.Input() && len(t.InputFields) == 0 && len(t.Fields) == 0) ||
+		((t.Interface() || t.Object()) && len(t.Fields) == 0) ||
 		(t.Union() && len(t.PossibleTypes) == 0) ||
 		(!t.Valid() && t.Name != "")
 
@@ -299,6 +350,12 @@ query op($typeName: String!){
 
 // GetTypeInfo fetches type information from remote endpoint
 func GetTypeInfo(cli *client.Client, typeName string, header http.Header) (Type, error) {
+	return GetTypeInfoContext(context.Background(), cli, typeName, header)
+}
+
+// GetTypeInfoContext fetches type information from remote endpoint
+// cancelling request when ctx is done
+func GetTypeInfoContext(ctx context.Context, cli *client.Client, typeName string, header http.Header) (Type, error) {
 	r := client.Raw{
 		Query:  typeInfoQuery,
 		Header: header,
@@ -309,7 +366,7 @@ func GetTypeInfo(cli *client.Client, typeName string, header http.Header) (Type,
 	out := struct {
 		Type Type `json:"__type,omitempty"`
 	}{}
-	if _, err := cli.Raw(r, &out); err != nil {
+	if _, err := cli.RawContext(ctx, r, &out); err != nil {
 		return Type{}, err
 	}
 	return out.Type, nil
@@ -326,6 +383,10 @@ type Schema struct {
 	MutationType Type `json:"mutationType,omitempty"`
 	// SubscriptionType is a reference to a type of subscription root operation
 	SubscriptionType Type `json:"subscriptionType,omitempty"`
+	// Directives is a list of directives supported by schema
+	Directives []Directive `json:"directives,omitempty"`
+	// Description is a schema defined description
+	Description string `json:"description,omitempty"`
 }
 
 // TypeForPath finds a type to which path would resolve to
@@ -367,6 +428,12 @@ func (s Schema) FieldForPath(path []string) (f Field, ok bool) {
 
 // GetSchemaTypes runs introspection query on remote endpoint returning schema
 func GetSchemaTypes(cli *client.Client, header http.Header) (Schema, error) {
+	return GetSchemaTypesContext(context.Background(), cli, header)
+}
+
+// GetSchemaTypesContext runs introspection query on remote endpoint
+// returning schema, request is cancelled when ctx is done
+func GetSchemaTypesContext(ctx context.Context, cli *client.Client, header http.Header) (Schema, error) {
 	r := client.Raw{
 		Query:  schemaInfoQuery,
 		Header: header,
@@ -374,7 +441,7 @@ func GetSchemaTypes(cli *client.Client, header http.Header) (Schema, error) {
 	out := struct {
 		Schema Schema `json:"__schema"`
 	}{}
-	if _, err := cli.Raw(r, &out); err != nil {
+	if _, err := cli.RawContext(ctx, r, &out); err != nil {
 		return Schema{}, err
 	}
 	return out.Schema, nil
@@ -0,0 +1,600 @@
+package introspection
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agnivade/levenshtein"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/printer"
+	"github.com/graphql-go/graphql/language/source"
+
+	"github.com/slothking-online/gql/client"
+)
+
+// nullLiteral replaces null values in query, as parser
+// does not support them, it has the same length as null
+// so that locations of errors do not change
+const nullLiteral = "__nl"
+
+// replaceNulls replaces null values in query with nullLiteral
+func replaceNulls(query string) string {
+	tokens := scanGraphQL(query)
+	src := []byte(query)
+	depth := 0
+	for i, t := range tokens {
+		switch t.value {
+		case "(", "[":
+			depth++
+		case ")", "]":
+			depth--
+		case "null":
+			// names of arguments and fields in values
+			// are followed by colon, variables follow $
+			if depth > 0 && (i+1 == len(tokens) || tokens[i+1].value != ":") && tokens[i-1].value != "$" {
+				copy(src[t.start:t.end], nullLiteral)
+			}
+		}
+	}
+	return string(src)
+}
+
+func isNull(val ast.Value) bool {
+	ev, ok := val.(*ast.EnumValue)
+	return ok && ev.Value == nullLiteral
+}
+
+// variableUsage is a variable used as a value of
+// argument, input field or list item
+type variableUsage struct {
+	node *ast.Variable
+	// typ is a type expected at location
+	typ Type
+	// hasDefault is true if location has default value
+	hasDefault bool
+}
+
+// validator checks GraphQL document against schema
+type validator struct {
+	schema     Schema
+	types      map[string]Type
+	directives map[string]Directive
+	src        *source.Source
+	errors     client.Errors
+	fragments  map[string]*ast.FragmentDefinition
+	// used fragments in whole document
+	used map[string]bool
+	// state of currently validated operation
+	spreads map[string]bool
+	stack   []string
+	usages  []variableUsage
+}
+
+func (v *validator) report(node ast.Node, format string, args ...interface{}) {
+	e := client.Error{Message: fmt.Sprintf(format, args...)}
+	if node != nil && node.GetLoc() != nil {
+		l := location.GetLocation(v.src, node.GetLoc().Start)
+		e.Locations = []client.Location{{Line: l.Line, Column: l.Column}}
+	}
+	// fragments are validated once for each
+	// operation spreading them, so report
+	// each error only once
+	for _, err := range v.errors {
+		if err.Message == e.Message && fmt.Sprint(err.Locations) == fmt.Sprint(e.Locations) {
+			return
+		}
+	}
+	v.errors = append(v.errors, e)
+}
+
+// deref returns named type definition of t
+func (v *validator) deref(t Type) (Type, bool) {
+	tt, ok := v.types[t.GetOfTypeLeaf().Name]
+	return tt, ok
+}
+
+func suggest(name string, candidates []string) string {
+	var matches []string
+	for _, c := range candidates {
+		if levenshtein.ComputeDistance(name, c) < 3 {
+			matches = append(matches, `"`+c+`"`)
+		}
+	}
+	if len(matches) == 0 {
+		return ""
+	}
+	return " Did you mean " + strings.Join(matches, " or ") + "?"
+}
+
+// astType converts type reference of variable definition
+func (v *validator) astType(t ast.Type) (Type, bool) {
+	switch tt := t.(type) {
+	case *ast.NonNull:
+		of, ok := v.astType(tt.Type)
+		return Type{Kind: "NON_NULL", OfType: &of}, ok
+	case *ast.List:
+		of, ok := v.astType(tt.Type)
+		return Type{Kind: "LIST", OfType: &of}, ok
+	case *ast.Named:
+		named, ok := v.types[tt.Name.Value]
+		return Type{Kind: named.Kind, Name: tt.Name.Value}, ok
+	}
+	return Type{}, false
+}
+
+// possibleTypes returns names of object types which
+// value of composite type t can be
+func possibleTypes(t Type) map[string]bool {
+	m := map[string]bool{}
+	if t.Object() {
+		m[t.Name] = true
+	}
+	for _, pt := range t.PossibleTypes {
+		m[pt.Name] = true
+	}
+	return m
+}
+
+func composite(t Type) bool {
+	return t.Object() || t.Interface() || t.Union()
+}
+
+func (v *validator) value(val ast.Value, t Type, hasDefault bool) {
+	if vr, ok := val.(*ast.Variable); ok {
+		v.usages = append(v.usages, variableUsage{node: vr, typ: t, hasDefault: hasDefault})
+		return
+	}
+	null := isNull(val)
+	switch {
+	case t.NonNull():
+		if null {
+			v.report(val, `Expected value of type "%s", found null.`, t.GoString())
+			return
+		}
+		v.value(val, *t.OfType, false)
+		return
+	case null:
+		return
+	case t.List():
+		if list, ok := val.(*ast.ListValue); ok {
+			for _, item := range list.Values {
+				v.value(item, *t.OfType, false)
+			}
+			return
+		}
+		v.value(val, *t.OfType, false)
+		return
+	}
+	named, ok := v.deref(t)
+	if !ok {
+		return
+	}
+	valid := true
+	switch {
+	case named.Scalar():
+		switch named.Name {
+		case "Int":
+			_, valid = val.(*ast.IntValue)
+		case "Float":
+			switch val.(type) {
+			case *ast.IntValue, *ast.FloatValue:
+			default:
+				valid = false
+			}
+		case "String":
+			_, valid = val.(*ast.StringValue)
+		case "Boolean":
+			_, valid = val.(*ast.BooleanValue)
+		case "ID":
+			switch val.(type) {
+			case *ast.IntValue, *ast.StringValue:
+			default:
+				valid = false
+			}
+		}
+	case named.Enum():
+		ev, ok := val.(*ast.EnumValue)
+		// values are unknown if schema was
+		// introspected without them
+		valid = ok && len(named.EnumValues) == 0
+		if ok {
+			for _, e := range named.EnumValues {
+				if e.Name == ev.Value {
+					valid = true
+					break
+				}
+			}
+		}
+	case named.Input():
+		obj, ok := val.(*ast.ObjectValue)
+		if !ok {
+			valid = false
+			break
+		}
+		// same as enum values, fields are
+		// unknown if schema has none
+		if len(named.InputFields) != 0 {
+			v.inputObject(obj, named)
+		}
+	}
+	if !valid {
+		v.report(val, `Expected value of type "%s", found %v.`, t.GoString(), printer.Print(val))
+	}
+}
+
+func (v *validator) inputObject(obj *ast.ObjectValue, t Type) {
+	set := map[string]bool{}
+	nonNull := 0
+	for _, f := range obj.Fields {
+		set[f.Name.Value] = true
+		if !isNull(f.Value) {
+			nonNull++
+		}
+		found := false
+		var names []string
+		for _, a := range t.InputFields {
+			names = append(names, a.Name)
+			if a.Name == f.Name.Value {
+				found = true
+				v.value(f.Value, a.Type, a.DefaultValue != "")
+			}
+		}
+		if !found {
+			v.report(f, `Field "%s" is not defined by type "%s".%s`, f.Name.Value, t.Name, suggest(f.Name.Value, names))
+		}
+	}
+	for _, a := range t.InputFields {
+		if required(a) && !set[a.Name] {
+			v.report(obj, `Field "%s.%s" of required type "%s" was not provided.`, t.Name, a.Name, a.Type.GoString())
+		}
+	}
+	if t.IsOneOf && (len(obj.Fields) != 1 || nonNull != 1) {
+		v.report(obj, `OneOf input object "%s" must specify exactly one non-null field.`, t.Name)
+	}
+}
+
+// arguments checks args against definitions, what is
+// a description of field or directive used in messages
+func (v *validator) arguments(node ast.Node, what string, args []*ast.Argument, defs []Arg) {
+	set := map[string]bool{}
+	for _, a := range args {
+		set[a.Name.Value] = true
+		found := false
+		var names []string
+		for _, def := range defs {
+			names = append(names, def.Name)
+			if def.Name == a.Name.Value {
+				found = true
+				v.value(a.Value, def.Type, def.DefaultValue != "")
+			}
+		}
+		if !found {
+			v.report(a, `Unknown argument "%s" on %s.%s`, a.Name.Value, what, suggest(a.Name.Value, names))
+		}
+	}
+	for _, def := range defs {
+		if required(def) && !set[def.Name] {
+			v.report(node, `Argument "%s" of type "%s" is required on %s, but it was not provided.`, def.Name, def.Type.GoString(), what)
+		}
+	}
+}
+
+func (v *validator) directiveList(directives []*ast.Directive) {
+	for _, d := range directives {
+		def, ok := v.directives[d.Name.Value]
+		if !ok {
+			if len(v.directives) != 0 {
+				v.report(d, `Unknown directive "@%s".`, d.Name.Value)
+			}
+			continue
+		}
+		v.arguments(d, `directive "@`+def.Name+`"`, d.Arguments, def.Args)
+	}
+}
+
+func (v *validator) field(parent Type, f *ast.Field) {
+	name := f.Name.Value
+	v.directiveList(f.Directives)
+	if name == "__typename" {
+		return
+	}
+	if (name == "__schema" || name == "__type") && parent.Name == v.schema.QueryType.Name {
+		// introspection types are not always a part of schema
+		return
+	}
+	var def *Field
+	var names []string
+	for i := range parent.Fields {
+		names = append(names, parent.Fields[i].Name)
+		if parent.Fields[i].Name == name {
+			def = &parent.Fields[i]
+		}
+	}
+	if def == nil {
+		v.report(f, `Cannot query field "%s" on type "%s".%s`, name, parent.Name, suggest(name, names))
+		return
+	}
+	v.arguments(f, `field "`+parent.Name+"."+name+`"`, f.Arguments, def.Args)
+	t, ok := v.deref(def.Type)
+	if !ok {
+		return
+	}
+	switch {
+	case composite(t) && f.SelectionSet == nil:
+		v.report(f, `Field "%s" of type "%s" must have a selection of subfields.`, name, def.Type.GoString())
+	case !composite(t) && f.SelectionSet != nil:
+		v.report(f, `Field "%s" must not have a selection since type "%s" has no subfields.`, name, def.Type.GoString())
+	case f.SelectionSet != nil:
+		v.selectionSet(t, f.SelectionSet)
+	}
+}
+
+// fragmentType checks type condition of fragment
+func (v *validator) fragmentType(node ast.Node, parent Type, cond *ast.Named, what string) (Type, bool) {
+	if cond == nil {
+		return parent, true
+	}
+	t, ok := v.types[cond.Name.Value]
+	if !ok {
+		v.report(cond, `Unknown type "%s".`, cond.Name.Value)
+		return Type{}, false
+	}
+	if !composite(t) {
+		v.report(cond, `Fragment cannot condition on non composite type "%s".`, t.Name)
+		return Type{}, false
+	}
+	pt := possibleTypes(parent)
+	for name := range possibleTypes(t) {
+		if pt[name] {
+			return t, true
+		}
+	}
+	v.report(node, `%s cannot be spread here as objects of type "%s" can never be of type "%s".`, what, parent.Name, t.Name)
+	return Type{}, false
+}
+
+func (v *validator) selectionSet(parent Type, ss *ast.SelectionSet) {
+	for _, sel := range ss.Selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			v.field(parent, s)
+		case *ast.InlineFragment:
+			v.directiveList(s.Directives)
+			if t, ok := v.fragmentType(s, parent, s.TypeCondition, "Fragment"); ok {
+				v.selectionSet(t, s.SelectionSet)
+			}
+		case *ast.FragmentSpread:
+			v.directiveList(s.Directives)
+			v.fragmentSpread(parent, s)
+		}
+	}
+}
+
+func (v *validator) fragmentSpread(parent Type, s *ast.FragmentSpread) {
+	name := s.Name.Value
+	frag, ok := v.fragments[name]
+	if !ok {
+		v.report(s, `Unknown fragment "%s".`, name)
+		return
+	}
+	v.used[name] = true
+	for _, n := range v.stack {
+		if n == name {
+			v.report(s, `Cannot spread fragment "%s" within itself.`, name)
+			return
+		}
+	}
+	t, ok := v.fragmentType(s, parent, frag.TypeCondition, `Fragment "`+name+`"`)
+	if !ok || v.spreads[name] {
+		return
+	}
+	v.spreads[name] = true
+	v.stack = append(v.stack, name)
+	v.selectionSet(t, frag.SelectionSet)
+	v.stack = v.stack[:len(v.stack)-1]
+}
+
+// variableAllowed returns true if variable of type vt
+// can be used where type t is expected
+func variableAllowed(vt, t Type) bool {
+	switch {
+	case t.NonNull():
+		return vt.NonNull() && variableAllowed(*vt.OfType, *t.OfType)
+	case vt.NonNull():
+		return variableAllowed(*vt.OfType, t)
+	case t.List():
+		return vt.List() && variableAllowed(*vt.OfType, *t.OfType)
+	case vt.List():
+		return false
+	}
+	return vt.Name == t.Name
+}
+
+func (v *validator) operation(op *ast.OperationDefinition) {
+	var root Type
+	switch op.Operation {
+	case ast.OperationTypeQuery:
+		root = v.schema.QueryType
+	case ast.OperationTypeMutation:
+		root = v.schema.MutationType
+	case ast.OperationTypeSubscription:
+		root = v.schema.SubscriptionType
+	}
+	t, ok := v.types[root.Name]
+	if root.Name == "" || !ok {
+		v.report(op, `Schema is not configured for %s operations.`, op.Operation)
+		return
+	}
+	opName := "anonymous operation"
+	if op.Name != nil {
+		opName = `operation "` + op.Name.Value + `"`
+	}
+	v.spreads = map[string]bool{}
+	v.usages = nil
+	v.directiveList(op.Directives)
+	vars := map[string]Type{}
+	defaults := map[string]bool{}
+	for _, vd := range op.VariableDefinitions {
+		name := vd.Variable.Name.Value
+		if _, ok := vars[name]; ok {
+			v.report(vd, `There can be only one variable named "$%s".`, name)
+		}
+		// variables of invalid type are still defined,
+		// but their usages are not checked
+		vars[name] = Type{}
+		vt, ok := v.astType(vd.Type)
+		named, _ := v.deref(vt)
+		switch {
+		case !ok:
+			v.report(vd.Type, `Unknown type "%s".`, printer.Print(vd.Type))
+			continue
+		case !named.Scalar() && !named.Enum() && !named.Input():
+			v.report(vd.Type, `Variable "$%s" cannot be non-input type "%s".`, name, vt.GoString())
+			continue
+		}
+		vars[name] = vt
+		if vd.DefaultValue != nil {
+			defaults[name] = true
+			v.value(vd.DefaultValue, vt, false)
+		}
+	}
+	v.selectionSet(t, op.SelectionSet)
+	used := map[string]bool{}
+	for _, u := range v.usages {
+		name := u.node.Name.Value
+		used[name] = true
+		vt, ok := vars[name]
+		if !ok {
+			v.report(u.node, `Variable "$%s" is not defined by %s.`, name, opName)
+			continue
+		}
+		if !vt.Valid() {
+			continue
+		}
+		if u.typ.NonNull() && !vt.NonNull() && (defaults[name] || u.hasDefault) {
+			// nullable variable with default value
+			// is allowed in non-null location
+			of := vt
+			vt = Type{Kind: "NON_NULL", OfType: &of}
+		}
+		if !variableAllowed(vt, u.typ) {
+			v.report(u.node, `Variable "$%s" of type "%s" used in position expecting type "%s".`, name, vt.GoString(), u.typ.GoString())
+		}
+	}
+	for _, vd := range op.VariableDefinitions {
+		if name := vd.Variable.Name.Value; !used[name] {
+			v.report(vd, `Variable "$%s" is never used in %s.`, name, opName)
+		}
+	}
+}
+
+// syntaxError converts parser error to GraphQL error,
+// leaving out source excerpt from message
+func syntaxError(err error) client.Errors {
+	gerr, ok := err.(*gqlerrors.Error)
+	if !ok {
+		return client.Errors{{Message: err.Error()}}
+	}
+	e := client.Error{Message: strings.SplitN(gerr.Message, "\n", 2)[0]}
+	for _, l := range gerr.Locations {
+		e.Locations = append(e.Locations, client.Location{Line: l.Line, Column: l.Column})
+	}
+	return client.Errors{e}
+}
+
+func newValidator(s Schema, src *source.Source) *validator {
+	v := &validator{
+		schema:     s,
+		types:      map[string]Type{},
+		directives: map[string]Directive{},
+		src:        src,
+		fragments:  map[string]*ast.FragmentDefinition{},
+		used:       map[string]bool{},
+		spreads:    map[string]bool{},
+	}
+	for _, t := range s.Types {
+		v.types[t.Name] = t
+	}
+	for _, d := range s.Directives {
+		v.directives[d.Name] = d
+	}
+	return v
+}
+
+// ValidateSelection checks selection, such as a:name owner { login },
+// added to a selection set of type typeName. Selection cannot use
+// variables or fragment spreads, as it is not a part of operation.
+func (s Schema) ValidateSelection(typeName, selection string) client.Errors {
+	src := source.NewSource(&source.Source{Body: []byte(replaceNulls("{" + selection + "}")), Name: "GraphQL"})
+	doc, err := parser.Parse(parser.ParseParams{Source: src})
+	if err != nil {
+		return syntaxError(err)
+	}
+	var op *ast.OperationDefinition
+	if len(doc.Definitions) == 1 {
+		op, _ = doc.Definitions[0].(*ast.OperationDefinition)
+	}
+	if op == nil {
+		return client.Errors{{Message: fmt.Sprintf("invalid selection %s", selection)}}
+	}
+	v := newValidator(s, src)
+	t, ok := v.types[typeName]
+	if !ok {
+		return client.Errors{{Message: fmt.Sprintf(`Unknown type "%s".`, typeName)}}
+	}
+	v.selectionSet(t, op.SelectionSet)
+	for _, u := range v.usages {
+		v.report(u.node, `Variable "$%s" is not defined.`, u.node.Name.Value)
+	}
+	return v.errors
+}
+
+// Validate checks query against schema before it is sent to
+// server, so that mistakes such as unknown fields, wrong
+// argument types or undefined variables are found without
+// a round trip. Errors are returned in the same format as
+// GraphQL errors reported by server, nil if query is valid.
+func (s Schema) Validate(query string) client.Errors {
+	src := source.NewSource(&source.Source{Body: []byte(replaceNulls(query)), Name: "GraphQL"})
+	doc, err := parser.Parse(parser.ParseParams{Source: src})
+	if err != nil {
+		return syntaxError(err)
+	}
+	v := newValidator(s, src)
+	var ops []*ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch d := def.(type) {
+		case *ast.OperationDefinition:
+			ops = append(ops, d)
+		case *ast.FragmentDefinition:
+			if _, ok := v.fragments[d.Name.Value]; ok {
+				v.report(d, `There can be only one fragment named "%s".`, d.Name.Value)
+			}
+			v.fragments[d.Name.Value] = d
+		default:
+			v.report(def, `The %s definition is not executable.`, def.GetKind())
+		}
+	}
+	names := map[string]bool{}
+	for _, op := range ops {
+		if op.Name == nil {
+			if len(ops) > 1 {
+				v.report(op, `This anonymous operation must be the only defined operation.`)
+			}
+		} else if names[op.Name.Value] {
+			v.report(op, `There can be only one operation named "%s".`, op.Name.Value)
+		} else {
+			names[op.Name.Value] = true
+		}
+		v.operation(op)
+	}
+	for _, def := range doc.Definitions {
+		if frag, ok := def.(*ast.FragmentDefinition); ok && !v.used[frag.Name.Value] {
+			v.report(frag, `Fragment "%s" is never used.`, frag.Name.Value)
+		}
+	}
+	return v.errors
+}
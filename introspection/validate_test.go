@@ -0,0 +1,288 @@
+package introspection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/slothking-online/gql/client"
+)
+
+const validateSchema = `
+type Query {
+  user(id: ID!): User
+  users(filter: Filter, first: Int = 10): [User!]!
+  node(id: ID!): Node
+  search(text: String!): [Result]
+  by(by: By): User
+}
+
+type Mutation {
+  setColor(color: Color!): User
+}
+
+interface Node {
+  id: ID!
+}
+
+type User implements Node {
+  id: ID!
+  name: String
+  color: Color
+  friends(first: Int): [User]
+}
+
+type Post implements Node {
+  id: ID!
+  title: String
+}
+
+union Result = User | Post
+
+enum Color {
+  RED
+  GREEN
+}
+
+input Filter {
+  name: String
+  colors: [Color!]
+  limit: Int!
+}
+
+input By @oneOf {
+  id: ID
+  name: String
+}
+`
+
+type testCaseValidate struct {
+	query  string
+	errors client.Errors
+}
+
+func (tt testCaseValidate) test(t *testing.T) {
+	assert := assert.New(t)
+	schema, err := ParseSDL(validateSchema)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(tt.errors, schema.Validate(tt.query))
+}
+
+func loc(line, column int) []client.Location {
+	return []client.Location{{Line: line, Column: column}}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	tests := map[string]testCaseValidate{
+		"Valid": {
+			query: `query Q($id: ID!, $colors: [Color!], $first: Int) {
+  user(id: $id) { id name friends(first: $first) { name } }
+  users(filter: {colors: $colors, limit: 1, name: null}) { ...F }
+  node(id: 1) { __typename ... on User { color } }
+  search(text: "a") { ... on Post { title } }
+}
+fragment F on Node { id }`,
+		},
+		"VariableWithDefaultInNonNullPosition": {
+			query: `query ($id: ID = "1") { user(id: $id) { id } }`,
+		},
+		"SyntaxError": {
+			query: `{ user(id: 1) { id }`,
+			errors: client.Errors{
+				{Message: "Syntax Error GraphQL (1:21) Expected Name, found EOF", Locations: loc(1, 21)},
+			},
+		},
+		"UnknownField": {
+			query: `{ user(id: 1) { nam } }`,
+			errors: client.Errors{
+				{Message: `Cannot query field "nam" on type "User". Did you mean "name"?`, Locations: loc(1, 17)},
+			},
+		},
+		"Selections": {
+			query: "{\n  user(id: 1)\n  node(id: 1) { id { x } }\n}",
+			errors: client.Errors{
+				{Message: `Field "user" of type "User" must have a selection of subfields.`, Locations: loc(2, 3)},
+				{Message: `Field "id" must not have a selection since type "ID!" has no subfields.`, Locations: loc(3, 17)},
+			},
+		},
+		"Arguments": {
+			query: `{ user(idd: 1) { id } users(first: "1", filter: {limit: 1, colors: [RED, BLUE]}) { id } }`,
+			errors: client.Errors{
+				{Message: `Unknown argument "idd" on field "Query.user". Did you mean "id"?`, Locations: loc(1, 8)},
+				{Message: `Argument "id" of type "ID!" is required on field "Query.user", but it was not provided.`, Locations: loc(1, 3)},
+				{Message: `Expected value of type "Int", found "1".`, Locations: loc(1, 36)},
+				{Message: `Expected value of type "Color", found BLUE.`, Locations: loc(1, 74)},
+			},
+		},
+		"InputObjects": {
+			query: `{ users(filter: {nme: "a"}) { id } a: by(by: {id: 1, name: "a"}) { id } b: by(by: {id: null}) { id } }`,
+			errors: client.Errors{
+				{Message: `Field "nme" is not defined by type "Filter". Did you mean "name"?`, Locations: loc(1, 18)},
+				{Message: `Field "Filter.limit" of required type "Int!" was not provided.`, Locations: loc(1, 17)},
+				{Message: `OneOf input object "By" must specify exactly one non-null field.`, Locations: loc(1, 46)},
+				{Message: `OneOf input object "By" must specify exactly one non-null field.`, Locations: loc(1, 83)},
+			},
+		},
+		"Null": {
+			query: `{ user(id: null) { id } }`,
+			errors: client.Errors{
+				{Message: `Expected value of type "ID!", found null.`, Locations: loc(1, 12)},
+			},
+		},
+		"Variables": {
+			query: `query Q($id: ID, $unused: Int, $name: String!) { user(id: $id) { id } node(id: $missing) { id } by(by: {name: $name}) { id } }`,
+			errors: client.Errors{
+				{Message: `Variable "$id" of type "ID" used in position expecting type "ID!".`, Locations: loc(1, 59)},
+				{Message: `Variable "$missing" is not defined by operation "Q".`, Locations: loc(1, 80)},
+				{Message: `Variable "$unused" is never used in operation "Q".`, Locations: loc(1, 18)},
+			},
+		},
+		"VariableTypes": {
+			query: `query ($a: Missing, $b: User) { user(id: 1) { id } users(filter: $a) { id } by(by: {id: $b}) { id } }`,
+			errors: client.Errors{
+				{Message: `Unknown type "Missing".`, Locations: loc(1, 12)},
+				{Message: `Variable "$b" cannot be non-input type "User".`, Locations: loc(1, 25)},
+			},
+		},
+		"Fragments": {
+			query: `{ node(id: 1) { ...A ...B ...C } search(text: "a") { ... on Color { x } } }
+fragment A on Post { title }
+fragment B on Missing { id }
+fragment D on User { id }`,
+			errors: client.Errors{
+				{Message: `Unknown type "Missing".`, Locations: loc(3, 15)},
+				{Message: `Unknown fragment "C".`, Locations: loc(1, 27)},
+				{Message: `Fragment cannot condition on non composite type "Color".`, Locations: loc(1, 61)},
+				{Message: `Fragment "D" is never used.`, Locations: loc(4, 1)},
+			},
+		},
+		"ImpossibleSpread": {
+			query: `{ user(id: 1) { ... on Post { id } ...P } }
+fragment P on Post { id }`,
+			errors: client.Errors{
+				{Message: `Fragment cannot be spread here as objects of type "User" can never be of type "Post".`, Locations: loc(1, 17)},
+				{Message: `Fragment "P" cannot be spread here as objects of type "User" can never be of type "Post".`, Locations: loc(1, 36)},
+			},
+		},
+		"FragmentCycle": {
+			query: `{ user(id: 1) { ...A } }
+fragment A on User { friends { ...B } }
+fragment B on User { friends { ...A } }`,
+			errors: client.Errors{
+				{Message: `Cannot spread fragment "A" within itself.`, Locations: loc(3, 32)},
+			},
+		},
+		"Operations": {
+			query: `mutation { setColor(color: RED) { id } } subscription { x } query A { user(id: 1) { id } } query A { user(id: 1) { id } }`,
+			errors: client.Errors{
+				{Message: `This anonymous operation must be the only defined operation.`, Locations: loc(1, 1)},
+				{Message: `This anonymous operation must be the only defined operation.`, Locations: loc(1, 42)},
+				{Message: `Schema is not configured for subscription operations.`, Locations: loc(1, 42)},
+				{Message: `There can be only one operation named "A".`, Locations: loc(1, 92)},
+			},
+		},
+		"Directives": {
+			query: `{ user(id: 1) @include(if: true) @skip(iff: false) @unknown { id } }`,
+			errors: client.Errors{
+				{Message: `Unknown argument "iff" on directive "@skip". Did you mean "if"?`, Locations: loc(1, 40)},
+				{Message: `Argument "if" of type "Boolean!" is required on directive "@skip", but it was not provided.`, Locations: loc(1, 34)},
+				{Message: `Unknown directive "@unknown".`, Locations: loc(1, 52)},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.test)
+	}
+}
+
+func TestSchemaValidateWithoutValues(t *testing.T) {
+	assert := assert.New(t)
+	schema, err := ParseSDL(validateSchema)
+	if !assert.NoError(err) {
+		return
+	}
+	// schema cached before enum values and input
+	// fields were introspected
+	for i, typ := range schema.Types {
+		switch typ.Name {
+		case "Color":
+			schema.Types[i].EnumValues = nil
+		case "Filter":
+			schema.Types[i].InputFields = nil
+		}
+	}
+	assert.Nil(schema.Validate(`mutation { setColor(color: BLUE) { id } }`))
+	assert.Nil(schema.Validate(`{ users(filter: {other: 1}) { id } }`))
+	assert.Equal(client.Errors{{
+		Message:   `Expected value of type "Color", found "RED".`,
+		Locations: loc(1, 28),
+	}}, schema.Validate(`mutation { setColor(color: "RED") { id } }`))
+}
+
+type testCaseValidateSelection struct {
+	typeName  string
+	selection string
+	errors    client.Errors
+}
+
+func (tt testCaseValidateSelection) test(t *testing.T) {
+	assert := assert.New(t)
+	schema, err := ParseSDL(validateSchema)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(tt.errors, schema.ValidateSelection(tt.typeName, tt.selection))
+}
+
+func TestSchemaValidateSelection(t *testing.T) {
+	tests := map[string]testCaseValidateSelection{
+		"Valid": {
+			typeName:  "User",
+			selection: `a:name friends(first: 1) { id } b: friends(first: 2) { name } ... on Node { id }`,
+		},
+		"UnknownField": {
+			typeName:  "User",
+			selection: "nam",
+			errors: client.Errors{{
+				Message:   `Cannot query field "nam" on type "User". Did you mean "name"?`,
+				Locations: loc(1, 2),
+			}},
+		},
+		"MissingSubselection": {
+			typeName:  "Query",
+			selection: "users",
+			errors: client.Errors{{
+				Message:   `Field "users" of type "[User!]!" must have a selection of subfields.`,
+				Locations: loc(1, 2),
+			}},
+		},
+		"Variable": {
+			typeName:  "User",
+			selection: "friends(first: $first) { id }",
+			errors: client.Errors{{
+				Message:   `Variable "$first" is not defined.`,
+				Locations: loc(1, 17),
+			}},
+		},
+		"Injected": {
+			typeName:  "User",
+			selection: "id } mutation { setColor(color: RED) { id }",
+			errors:    client.Errors{{Message: "invalid selection id } mutation { setColor(color: RED) { id }"}},
+		},
+		"Syntax": {
+			typeName:  "User",
+			selection: "friends {",
+			errors:    client.Errors{{Message: "Syntax Error GraphQL (1:10) Unexpected empty IN {}", Locations: loc(1, 10)}},
+		},
+		"UnknownType": {
+			typeName:  "Missing",
+			selection: "id",
+			errors:    client.Errors{{Message: `Unknown type "Missing".`}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.test)
+	}
+}
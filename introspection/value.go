@@ -0,0 +1,232 @@
+package introspection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// ParseValue parses GraphQL input value literal, such as
+// {ids: [1, 2], name: "a"}, into a value that can be sent
+// as query variable. Enum values become strings and numbers
+// are kept as json.Number, so that they are sent as written.
+func ParseValue(literal string) (interface{}, error) {
+	// parser does not parse bare values, so value is
+	// parsed as an argument of a field
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: replaceNulls("{f(v: " + literal + ")}"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %s", literal)
+	}
+	if len(doc.Definitions) == 1 {
+		if op, ok := doc.Definitions[0].(*ast.OperationDefinition); ok && len(op.SelectionSet.Selections) == 1 {
+			if f, ok := op.SelectionSet.Selections[0].(*ast.Field); ok && len(f.Arguments) == 1 && f.SelectionSet == nil {
+				return valueOf(f.Arguments[0].Value)
+			}
+		}
+	}
+	return nil, fmt.Errorf("invalid value %s", literal)
+}
+
+func valueOf(val ast.Value) (interface{}, error) {
+	switch v := val.(type) {
+	case *ast.IntValue:
+		return json.Number(v.Value), nil
+	case *ast.FloatValue:
+		return json.Number(v.Value), nil
+	case *ast.StringValue:
+		return v.Value, nil
+	case *ast.BooleanValue:
+		return v.Value, nil
+	case *ast.EnumValue:
+		if isNull(v) {
+			return nil, nil
+		}
+		return v.Value, nil
+	case *ast.ListValue:
+		list := make([]interface{}, 0, len(v.Values))
+		for _, item := range v.Values {
+			iv, err := valueOf(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, iv)
+		}
+		return list, nil
+	case *ast.ObjectValue:
+		obj := make(map[string]interface{}, len(v.Fields))
+		for _, f := range v.Fields {
+			fv, err := valueOf(f.Value)
+			if err != nil {
+				return nil, err
+			}
+			obj[f.Name.Value] = fv
+		}
+		return obj, nil
+	case *ast.Variable:
+		return nil, fmt.Errorf("variable $%s cannot be used in value", v.Name.Value)
+	}
+	return nil, fmt.Errorf("unsupported value %s", val.GetKind())
+}
+
+// ValidateValue checks value of a query variable, such as one
+// returned by ParseValue or decoded from JSON, against type t.
+// Name of the variable is used as path in error messages.
+func (s Schema) ValidateValue(name string, value interface{}, t Type) error {
+	v := valueValidator{schema: s}
+	v.value(name, value, t)
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(v.errors, "\n"))
+}
+
+type valueValidator struct {
+	schema Schema
+	errors []string
+}
+
+func (v *valueValidator) report(format string, args ...interface{}) {
+	v.errors = append(v.errors, fmt.Sprintf(format, args...))
+}
+
+// number returns numeric value of val
+func number(val interface{}) (float64, bool) {
+	switch n := val.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func integer(val interface{}) bool {
+	n, ok := number(val)
+	return ok && n == math.Trunc(n) && n >= math.MinInt32 && n <= math.MaxInt32
+}
+
+func (v *valueValidator) value(path string, val interface{}, t Type) {
+	switch {
+	case t.NonNull():
+		if val == nil {
+			v.report(`Expected value of type "%s" at "%s", found null.`, t.GoString(), path)
+			return
+		}
+		v.value(path, val, *t.OfType)
+		return
+	case val == nil:
+		return
+	case t.List():
+		if list, ok := val.([]interface{}); ok {
+			for i, item := range list {
+				v.value(fmt.Sprintf("%s.%d", path, i), item, *t.OfType)
+			}
+			return
+		}
+		v.value(path, val, *t.OfType)
+		return
+	}
+	named := t.Deref(v.schema.Types)
+	if !named.Valid() {
+		named = t
+	}
+	valid := true
+	hint := ""
+	switch {
+	case named.Scalar():
+		switch named.Name {
+		case "Int":
+			valid = integer(val)
+		case "Float":
+			_, valid = number(val)
+		case "String":
+			_, valid = val.(string)
+		case "Boolean":
+			_, valid = val.(bool)
+		case "ID":
+			_, valid = val.(string)
+			valid = valid || integer(val)
+		}
+	case named.Enum():
+		s, ok := val.(string)
+		// values are unknown if schema was
+		// introspected without them
+		valid = ok && len(named.EnumValues) == 0
+		names := make([]string, 0, len(named.EnumValues))
+		for _, e := range named.EnumValues {
+			names = append(names, e.Name)
+			valid = valid || (ok && e.Name == s)
+		}
+		if ok {
+			hint = suggest(s, names)
+		}
+	case named.Input():
+		obj, ok := val.(map[string]interface{})
+		if !ok {
+			valid = false
+			break
+		}
+		if len(named.InputFields) != 0 {
+			v.inputObject(path, obj, named)
+		}
+	}
+	if !valid {
+		found, _ := json.Marshal(val)
+		v.report(`Expected value of type "%s" at "%s", found %s.%s`, t.GoString(), path, found, hint)
+	}
+}
+
+func (v *valueValidator) inputObject(path string, obj map[string]interface{}, t Type) {
+	names := make([]string, 0, len(t.InputFields))
+	for _, a := range t.InputFields {
+		names = append(names, a.Name)
+		fv, ok := obj[a.Name]
+		if !ok {
+			if required(a) {
+				v.report(`Field "%s.%s" of required type "%s" was not provided.`, path, a.Name, a.Type.GoString())
+			}
+			continue
+		}
+		v.value(path+"."+a.Name, fv, a.Type)
+	}
+	keys := make([]string, 0, len(obj))
+	nonNull := 0
+	for k, fv := range obj {
+		keys = append(keys, k)
+		if fv != nil {
+			nonNull++
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		found := false
+		for _, name := range names {
+			found = found || name == k
+		}
+		if !found {
+			v.report(`Field "%s.%s" is not defined by type "%s".%s`, path, k, t.Name, suggest(k, names))
+		}
+	}
+	if t.IsOneOf && (len(obj) != 1 || nonNull != 1) {
+		v.report(`OneOf input object "%s" at "%s" must specify exactly one non-null field.`, t.Name, path)
+	}
+}
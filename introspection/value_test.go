@@ -0,0 +1,178 @@
+package introspection
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aexol/test_util"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCaseParseValue struct {
+	literal string
+	value   interface{}
+	err     func(*assert.Assertions) test_util.ErrorAssertion
+}
+
+func (tt testCaseParseValue) test(t *testing.T) {
+	assert := assert.New(t)
+	if tt.err == nil {
+		tt.err = test_util.NoError
+	}
+	v, err := ParseValue(tt.literal)
+	tt.err(assert)(err)
+	assert.Equal(tt.value, v)
+}
+
+func TestParseValue(t *testing.T) {
+	tests := map[string]testCaseParseValue{
+		"Int":    {literal: "12345678901234567890", value: json.Number("12345678901234567890")},
+		"Float":  {literal: "1.5e3", value: json.Number("1.5e3")},
+		"String": {literal: `"a \"b\"\n"`, value: "a \"b\"\n"},
+		"Bool":   {literal: "true", value: true},
+		"Enum":   {literal: "RED", value: "RED"},
+		"Null":   {literal: "null"},
+		"List":   {literal: "[1, null, [a]]", value: []interface{}{json.Number("1"), nil, []interface{}{"a"}}},
+		"Object": {
+			literal: `{name: "x", null: null, nested: {ids: ["1"]}}`,
+			value: map[string]interface{}{
+				"name":   "x",
+				"null":   nil,
+				"nested": map[string]interface{}{"ids": []interface{}{"1"}},
+			},
+		},
+		"Variable": {literal: "$a", err: test_util.Error},
+		"Invalid":  {literal: "{a", err: test_util.Error},
+		"Injected": {literal: "1) b(v: 2", err: test_util.Error},
+		"Empty":    {literal: "", err: test_util.Error},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.test)
+	}
+}
+
+const valueSchema = `
+enum Color { RED GREEN }
+input Post {
+  title: String!
+  count: Int = 1
+  score: Float
+  id: ID
+  done: Boolean
+  color: Color
+  tags: [String!]
+  author: Author
+}
+input Author { name: String! }
+input One @oneOf { a: Int, b: Int }
+type Query { post(input: Post!, one: One): String }
+`
+
+func TestSchemaValidateValueWithoutValues(t *testing.T) {
+	assert := assert.New(t)
+	s, err := ParseSDL(valueSchema)
+	if !assert.NoError(err) {
+		return
+	}
+	for i, typ := range s.Types {
+		switch typ.Name {
+		case "Color":
+			s.Types[i].EnumValues = nil
+		case "Author":
+			s.Types[i].InputFields = nil
+		}
+	}
+	input := s.QueryType.Deref(s.Types).Fields[0].Args[0]
+	assert.NoError(s.ValidateValue(input.Name, map[string]interface{}{
+		"title":  "x",
+		"color":  "BLUE",
+		"author": map[string]interface{}{"other": "y"},
+	}, input.Type))
+}
+
+type testCaseValidateValue struct {
+	value interface{}
+	typ   string
+	err   string
+}
+
+func (tt testCaseValidateValue) test(t *testing.T) {
+	assert := assert.New(t)
+	s, err := ParseSDL(valueSchema)
+	if !assert.NoError(err) {
+		return
+	}
+	for _, arg := range s.QueryType.Deref(s.Types).Fields[0].Args {
+		if arg.Name != tt.typ {
+			continue
+		}
+		err := s.ValidateValue(arg.Name, tt.value, arg.Type)
+		if tt.err == "" {
+			assert.NoError(err)
+		} else if assert.Error(err) {
+			assert.Equal(tt.err, err.Error())
+		}
+		return
+	}
+	t.Fatalf("argument %s not found", tt.typ)
+}
+
+func TestSchemaValidateValue(t *testing.T) {
+	tests := map[string]testCaseValidateValue{
+		"Valid": {
+			typ: "input",
+			value: map[string]interface{}{
+				"title":  "x",
+				"count":  json.Number("2"),
+				"score":  1.5,
+				"id":     int64(1),
+				"done":   true,
+				"color":  "RED",
+				"tags":   []interface{}{"a"},
+				"author": map[string]interface{}{"name": "a"},
+			},
+		},
+		"Coerced": {
+			typ:   "input",
+			value: map[string]interface{}{"title": "x", "tags": "a", "author": nil},
+		},
+		"Null": {
+			typ: "input",
+			err: `Expected value of type "Post!" at "input", found null.`,
+		},
+		"Invalid": {
+			typ: "input",
+			value: map[string]interface{}{
+				"title":  "x",
+				"count":  1.5,
+				"color":  "BLUE",
+				"tags":   []interface{}{"a", nil},
+				"author": map[string]interface{}{},
+				"titel":  "y",
+			},
+			err: `Expected value of type "Int" at "input.count", found 1.5.
+Expected value of type "Color" at "input.color", found "BLUE".
+Expected value of type "String!" at "input.tags.1", found null.
+Field "input.author.name" of required type "String!" was not provided.
+Field "input.titel" is not defined by type "Post". Did you mean "title"?`,
+		},
+		"EnumSuggestion": {
+			typ:   "input",
+			value: map[string]interface{}{"title": "x", "color": "GREEM"},
+			err:   `Expected value of type "Color" at "input.color", found "GREEM". Did you mean "GREEN"?`,
+		},
+		"Missing": {
+			typ:   "input",
+			value: map[string]interface{}{},
+			err:   `Field "input.title" of required type "String!" was not provided.`,
+		},
+		"OneOf": {
+			typ:   "one",
+			value: map[string]interface{}{"a": json.Number("1"), "b": json.Number("2")},
+			err:   `OneOf input object "One" at "one" must specify exactly one non-null field.`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, tt.test)
+	}
+}